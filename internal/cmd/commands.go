@@ -4,10 +4,27 @@
 
 package cmd
 
-import "github.com/rs/zerolog/log"
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+)
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal().Err(err).Msg("Could not execute root command!")
 	}
 }
+
+// getStoreConfig returns the configuration of the stores of the given mode.
+func getStoreConfig(mode string) (*config.DualStore, error) {
+	switch config.Mode(mode) {
+	case config.ModeProvisioning:
+		return &config.Current.Provisioning.Store, nil
+	case config.ModeWatcher:
+		return &config.Current.Watcher.Store, nil
+	default:
+		return nil, fmt.Errorf("invalid mode %q: must be 'provisioning' or 'watcher'", mode)
+	}
+}
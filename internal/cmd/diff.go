@@ -0,0 +1,66 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/telekom/quasar/internal/config"
+)
+
+var errInconsistentStores = errors.New("stores are not consistent with their data source")
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Reports the differences a reconciliation would resolve in the configured stores without writing anything",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setup, err := setupReconciler("DiffStore", cmd)
+		if err != nil {
+			return err
+		}
+		defer setup.manager.Shutdown()
+
+		var errs []error
+		inconsistent := false
+		for _, resourceConfig := range setup.resources {
+			reports, err := setup.reconciler.DiffReconciliation(cmd.Context(), setup.dataSource(resourceConfig), resourceConfig)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			for _, report := range reports {
+				log.Info().
+					Str("dataset", report.Dataset).
+					Str("store", report.Store).
+					Strs("missing", report.Missing).
+					Strs("stale", report.Stale).
+					Strs("orphaned", report.Orphaned).
+					Interface("samples", report.Samples).
+					Bool("consistent", report.Consistent()).
+					Msg("Compared store with data source")
+
+				if !report.Consistent() {
+					inconsistent = true
+				}
+			}
+		}
+
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+		if inconsistent {
+			return errInconsistentStores
+		}
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringSlice("dataset", nil, "datasets that should be compared (all configured resources if unset)")
+	diffCmd.Flags().String("mode", string(config.Current.Mode), "mode whose stores should be compared ('provisioning' or 'watcher')")
+	diffCmd.Flags().StringP("kubeconfig", "k", "", "sets the kubeconfig that should be used in watcher mode (service account will be used if unset)")
+}
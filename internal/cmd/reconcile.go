@@ -0,0 +1,128 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/k8s"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/store"
+	"k8s.io/client-go/dynamic"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Reconciles the configured stores with their data source once",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setup, err := setupReconciler("ReconcileStore", cmd)
+		if err != nil {
+			return err
+		}
+		defer setup.manager.Shutdown()
+
+		var errs []error
+		for _, resourceConfig := range setup.resources {
+			if !resourceConfig.Reconciliation.IsEnabled() {
+				log.Info().Str("dataset", resourceConfig.GetGroupVersionName()).Msg("Reconciliation is disabled, skipping")
+				continue
+			}
+
+			results, err := setup.reconciler.Reconcile(cmd.Context(), setup.dataSource(resourceConfig), resourceConfig)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			for _, result := range results {
+				log.Info().
+					Str("dataset", result.Dataset).
+					Str("store", result.Store).
+					Int("added", result.Added).
+					Int("updated", result.Updated).
+					Int("removed", result.Removed).
+					Int("skippedOrphans", result.SkippedOrphans).
+					Msg("Reconciled store")
+			}
+		}
+
+		return errors.Join(errs...)
+	},
+}
+
+// reconcilerSetup holds the store manager of the commands that reconcile stores together with the resources that
+// should be reconciled. In watcher mode, the stores are reconciled against kubernetes, in provisioning mode, the
+// replicas are reconciled against the authoritative store.
+type reconcilerSetup struct {
+	manager          store.DualStore
+	reconciler       store.Reconciler
+	resources        []*config.Resource
+	kubernetesClient dynamic.Interface
+}
+
+// setupReconciler creates the store manager of the mode and selects the resources of the datasets given as flags.
+func setupReconciler(id string, cmd *cobra.Command) (*reconcilerSetup, error) {
+	datasets, _ := cmd.Flags().GetStringSlice("dataset")
+	mode, _ := cmd.Flags().GetString("mode")
+	kubeConfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+	storeConfig, err := getStoreConfig(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	setup := new(reconcilerSetup)
+	if config.Mode(mode) == config.ModeWatcher {
+		if setup.kubernetesClient, err = createKubernetesClient(kubeConfigPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range config.Current.Resources {
+		resourceConfig := &config.Current.Resources[i]
+		if len(datasets) == 0 || slices.Contains(datasets, resourceConfig.GetGroupVersionName()) {
+			setup.resources = append(setup.resources, resourceConfig)
+		}
+	}
+
+	// the periodic verification must not run concurrently
+	reconcileConfig := *storeConfig
+	reconcileConfig.Verification.Enabled = false
+
+	if setup.manager, err = store.SetupStoreManager(id, &reconcileConfig); err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if setup.reconciler, ok = setup.manager.(store.Reconciler); !ok {
+		setup.manager.Shutdown()
+		return nil, errors.New("store manager does not support reconciliation")
+	}
+	return setup, nil
+}
+
+// dataSource returns the data source the stores are reconciled against.
+func (s *reconcilerSetup) dataSource(resourceConfig *config.Resource) reconciliation.DataSource {
+	if s.kubernetesClient != nil {
+		return reconciliation.NewDataSourceFromKubernetesClient(s.kubernetesClient, resourceConfig)
+	}
+	return reconciliation.NewDataSourceFromStore(s.manager, *resourceConfig)
+}
+
+func createKubernetesClient(kubeConfigPath string) (dynamic.Interface, error) {
+	if len(kubeConfigPath) == 0 {
+		return k8s.CreateInClusterClient()
+	}
+	return k8s.CreateKubeConfigClient(kubeConfigPath)
+}
+
+func init() {
+	reconcileCmd.Flags().StringSlice("dataset", nil, "datasets that should be reconciled (all configured resources if unset)")
+	reconcileCmd.Flags().String("mode", string(config.Current.Mode), "mode whose stores should be reconciled ('provisioning' or 'watcher')")
+	reconcileCmd.Flags().StringP("kubeconfig", "k", "", "sets the kubeconfig that should be used in watcher mode (service account will be used if unset)")
+}
@@ -13,5 +13,5 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
-	rootCmd.AddCommand(initCmd, runCmd)
+	rootCmd.AddCommand(initCmd, runCmd, verifyCmd, diffCmd, reconcileCmd)
 }
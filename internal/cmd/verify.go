@@ -0,0 +1,84 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/store"
+)
+
+var errInconsistentReplicas = errors.New("replicas are not consistent with the authoritative store")
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Compares the replicas of the configured stores with the authoritative store and optionally repairs them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repair, _ := cmd.Flags().GetBool("repair")
+		datasets, _ := cmd.Flags().GetStringSlice("dataset")
+		mode, _ := cmd.Flags().GetString("mode")
+
+		storeConfig, err := getStoreConfig(mode)
+		if err != nil {
+			return err
+		}
+
+		if len(datasets) == 0 {
+			for _, resourceConfig := range config.Current.Resources {
+				datasets = append(datasets, resourceConfig.GetGroupVersionName())
+			}
+		}
+
+		// the periodic verification must not run concurrently
+		verifyConfig := *storeConfig
+		verifyConfig.Verification.Enabled = false
+
+		manager, err := store.SetupStoreManager("VerifyStore", &verifyConfig)
+		if err != nil {
+			return err
+		}
+		defer manager.Shutdown()
+
+		verifier, ok := manager.(store.Verifier)
+		if !ok {
+			return errors.New("store manager does not support verification")
+		}
+
+		reports, err := verifier.Verify(cmd.Context(), datasets, repair)
+		inconsistent := false
+		for _, report := range reports {
+			log.Info().
+				Str("dataset", report.Dataset).
+				Str("replica", report.Replica).
+				Strs("missing", report.Missing).
+				Strs("stale", report.Stale).
+				Strs("orphaned", report.Orphaned).
+				Int("repaired", report.Repaired).
+				Bool("consistent", report.Consistent()).
+				Msg("Verified replica")
+
+			if !report.Consistent() && !repair {
+				inconsistent = true
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+		if inconsistent {
+			return errInconsistentReplicas
+		}
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().Bool("repair", false, "writes missing and stale resources to the replicas and deletes orphaned resources from them")
+	verifyCmd.Flags().StringSlice("dataset", nil, "datasets that should be verified (all configured resources if unset)")
+	verifyCmd.Flags().String("mode", string(config.Current.Mode), "mode whose stores should be verified ('provisioning' or 'watcher')")
+}
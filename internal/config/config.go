@@ -5,6 +5,10 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/hazelcast/hazelcast-go-client/cluster"
@@ -19,15 +23,23 @@ type Configuration struct {
 	ReSyncPeriod time.Duration `mapstructure:"reSyncPeriod"`
 	Resources    []Resource    `mapstructure:"resources"`
 	Store        struct {
-		Redis     Redis     `mapstructure:"redis"`
-		Hazelcast Hazelcast `mapstructure:"hazelcast"`
-		Mongo     Mongo     `mapstructure:"mongo"`
+		Redis          Redis          `mapstructure:"redis"`
+		Hazelcast      Hazelcast      `mapstructure:"hazelcast"`
+		Mongo          Mongo          `mapstructure:"mongo"`
+		Memory         Memory         `mapstructure:"memory"`
+		File           File           `mapstructure:"file"`
+		Postgres       Postgres       `mapstructure:"postgres"`
+		Kafka          Kafka          `mapstructure:"kafka"`
+		Webhook        Webhook        `mapstructure:"webhook"`
+		RetryQueue     RetryQueue     `mapstructure:"retryQueue"`
+		CircuitBreaker CircuitBreaker `mapstructure:"circuitBreaker"`
 	} `mapstructure:"store"`
 	Fallback struct {
 		Type  string `mapstructure:"type"`
 		Mongo Mongo  `mapstructure:"mongo"`
 	} `mapstructure:"fallback"`
-	Metrics Metrics `mapstructure:"metrics"`
+	Metrics        Metrics        `mapstructure:"metrics"`
+	Reconciliation Reconciliation `mapstructure:"reconciliation"`
 }
 
 // GetResourceConfiguration returns a resource configuration for the given object if applicable.
@@ -48,11 +60,64 @@ func (c *Configuration) GetResourceConfiguration(obj *unstructured.Unstructured)
 }
 
 type Redis struct {
-	Host     string `mapstructure:"host"`
-	Port     uint   `mapstructure:"port"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
-	Database int    `mapstructure:"database"`
+	Mode                   RedisMode     `mapstructure:"mode"`
+	Host                   string        `mapstructure:"host"`
+	Port                   uint          `mapstructure:"port"`
+	Addresses              []string      `mapstructure:"addresses"`
+	Username               string        `mapstructure:"username"`
+	Password               string        `mapstructure:"password"`
+	Database               int           `mapstructure:"database"`
+	Sentinel               RedisSentinel `mapstructure:"sentinel"`
+	TLS                    RedisTLS      `mapstructure:"tls"`
+	ReconciliationInterval time.Duration `mapstructure:"reconciliationInterval"`
+	OperationTimeout       time.Duration `mapstructure:"operationTimeout"`
+}
+
+type RedisSentinel struct {
+	MasterName string `mapstructure:"masterName"`
+	Username   string `mapstructure:"username"`
+	Password   string `mapstructure:"password"`
+}
+
+type RedisTLS struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"caFile"`
+	CertFile           string `mapstructure:"certFile"`
+	KeyFile            string `mapstructure:"keyFile"`
+	ServerName         string `mapstructure:"serverName"`
+	InsecureSkipVerify bool   `mapstructure:"insecureSkipVerify"`
+}
+
+// ToTLSConfig creates the tls configuration for the redis client.
+func (t *RedisTLS) ToTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint:gosec // explicitly requested by configuration
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("could not parse any certificate from %s", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
 }
 
 type Hazelcast struct {
@@ -66,6 +131,7 @@ type Hazelcast struct {
 	HeartbeatTimeout       time.Duration               `mapstructure:"heartbeatTimeout"`
 	ConnectionTimeout      time.Duration               `mapstructure:"connectionTimeout"`
 	InvocationTimeout      time.Duration               `mapstructure:"invocationTimeout"`
+	OperationTimeout       time.Duration               `mapstructure:"operationTimeout"`
 	RedoOperation          bool                        `mapstructure:"redoOperation"`
 	ConnectionStrategy     HazelcastConnectionStrategy `mapstructure:"connectionStrategy"`
 }
@@ -84,8 +150,73 @@ type HazelcastRetry struct {
 }
 
 type Mongo struct {
-	Uri      string `mapstructure:"uri"`
-	Database string `mapstructure:"database"`
+	Uri              string        `mapstructure:"uri"`
+	Database         string        `mapstructure:"database"`
+	OperationTimeout time.Duration `mapstructure:"operationTimeout"`
+}
+
+type Memory struct {
+	SnapshotPath string `mapstructure:"snapshotPath"`
+}
+
+type File struct {
+	Path        string        `mapstructure:"path"`
+	OpenTimeout time.Duration `mapstructure:"openTimeout"`
+}
+
+type Postgres struct {
+	Uri              string        `mapstructure:"uri"`
+	Schema           string        `mapstructure:"schema"`
+	OperationTimeout time.Duration `mapstructure:"operationTimeout"`
+}
+
+type Kafka struct {
+	Brokers    []string      `mapstructure:"brokers"`
+	ClientId   string        `mapstructure:"clientId"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	Tombstones bool          `mapstructure:"tombstones"`
+}
+
+type Webhook struct {
+	Url              string        `mapstructure:"url"`
+	Source           string        `mapstructure:"source"`
+	Secret           string        `mapstructure:"secret"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	Retry            WebhookRetry  `mapstructure:"retry"`
+	OperationTimeout time.Duration `mapstructure:"operationTimeout"`
+}
+
+type WebhookRetry struct {
+	MaxAttempts    int           `mapstructure:"maxAttempts"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+	Multiplier     float64       `mapstructure:"multiplier"`
+}
+
+type RetryQueue struct {
+	Capacity       int           `mapstructure:"capacity"`
+	Workers        int           `mapstructure:"workers"`
+	MaxAttempts    int           `mapstructure:"maxAttempts"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+	Multiplier     float64       `mapstructure:"multiplier"`
+	Path           string        `mapstructure:"path"`
+	DrainTimeout   time.Duration `mapstructure:"drainTimeout"`
+}
+
+type CircuitBreaker struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	FailureThreshold int           `mapstructure:"failureThreshold"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	OpenDuration     time.Duration `mapstructure:"openDuration"`
+	HalfOpenProbes   int           `mapstructure:"halfOpenProbes"`
+}
+
+type Reconciliation struct {
+	Mode            ReconcileMode `mapstructure:"mode"`
+	Interval        time.Duration `mapstructure:"interval"`
+	DeleteOrphans   bool          `mapstructure:"deleteOrphans"`
+	OrphanThreshold float64       `mapstructure:"orphanThreshold"`
 }
 
 type Metrics struct {
@@ -94,15 +225,74 @@ type Metrics struct {
 	Timeout time.Duration `mapstructure:"timeout"`
 }
 
+// DualStore configures the stores of a store manager. Either an ordered list of stores or, for compatibility,
+// a primary and an optional secondary store can be configured.
 type DualStore struct {
-	Primary   Store `mapstructure:"primary"`
-	Secondary Store `mapstructure:"secondary"`
+	Primary      Store            `mapstructure:"primary"`
+	Secondary    Store            `mapstructure:"secondary"`
+	Stores       []Store          `mapstructure:"stores"`
+	Consistency  WriteConsistency `mapstructure:"consistency"`
+	ReadFailover ReadFailover     `mapstructure:"readFailover"`
+	Verification Verification     `mapstructure:"verification"`
+}
+
+type Verification struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	Repair   bool          `mapstructure:"repair"`
+}
+
+type ReadFailover struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	MaxStaleness time.Duration `mapstructure:"maxStaleness"`
 }
 
 type Store struct {
-	Type string `mapstructure:"type"`
+	Type        string           `mapstructure:"type"`
+	Role        StoreRole        `mapstructure:"role"`
+	WritePolicy StoreWritePolicy `mapstructure:"writePolicy"`
+}
+
+// GetStores returns the configured list of stores. If no list is configured, the primary store is returned as
+// authoritative store followed by the secondary store, unless it is empty or of the same type as the primary store.
+func (s *DualStore) GetStores() []Store {
+	if len(s.Stores) > 0 {
+		return s.Stores
+	}
+
+	if s.Primary.Type == "" {
+		return nil
+	}
+
+	primary := s.Primary
+	primary.Role = StoreRoleAuthoritative
+	stores := []Store{primary}
+	if s.Secondary.Type != "" && s.Secondary.Type != s.Primary.Type {
+		stores = append(stores, s.Secondary)
+	}
+	return stores
 }
 
 type Watcher struct {
-	Store DualStore `mapstructure:"store"`
+	Store          DualStore      `mapstructure:"store"`
+	Queue          WatcherQueue   `mapstructure:"queue"`
+	LeaderElection LeaderElection `mapstructure:"leaderElection"`
+}
+
+// WatcherQueue configures the queues through which the events of the watchers are written to the store.
+type WatcherQueue struct {
+	Workers        int           `mapstructure:"workers"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+	MaxRetries     int           `mapstructure:"maxRetries"`
+}
+
+// LeaderElection configures the election of the watcher instance that writes to the stores.
+type LeaderElection struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	LeaseName      string        `mapstructure:"leaseName"`
+	LeaseNamespace string        `mapstructure:"leaseNamespace"`
+	LeaseDuration  time.Duration `mapstructure:"leaseDuration"`
+	RenewDeadline  time.Duration `mapstructure:"renewDeadline"`
+	RetryPeriod    time.Duration `mapstructure:"retryPeriod"`
 }
@@ -40,6 +40,12 @@ func setDefaults() {
 
 	viper.SetDefault("provisioning.store.primary.type", "mongo")
 	viper.SetDefault("provisioning.store.secondary.type", "hazelcast")
+	viper.SetDefault("provisioning.store.consistency", WriteConsistencyPrimaryOnlyAck)
+	viper.SetDefault("provisioning.store.readFailover.enabled", false)
+	viper.SetDefault("provisioning.store.readFailover.maxStaleness", "0s")
+	viper.SetDefault("provisioning.store.verification.enabled", false)
+	viper.SetDefault("provisioning.store.verification.interval", "10m")
+	viper.SetDefault("provisioning.store.verification.repair", false)
 
 	viper.SetDefault("provisioning.security.enabled", true)
 	viper.SetDefault("provisioning.security.trustedIssuers", []string{"https://auth.example.com/certs"})
@@ -47,12 +53,41 @@ func setDefaults() {
 
 	viper.SetDefault("watcher.store.primary.type", "hazelcast")
 	viper.SetDefault("watcher.store.secondary.type", "mongo")
-
+	viper.SetDefault("watcher.store.consistency", WriteConsistencyPrimaryOnlyAck)
+	viper.SetDefault("watcher.store.readFailover.enabled", false)
+	viper.SetDefault("watcher.store.readFailover.maxStaleness", "0s")
+	viper.SetDefault("watcher.store.verification.enabled", false)
+	viper.SetDefault("watcher.store.verification.interval", "10m")
+	viper.SetDefault("watcher.store.verification.repair", false)
+	viper.SetDefault("watcher.queue.workers", 1)
+	viper.SetDefault("watcher.queue.initialBackoff", "100ms")
+	viper.SetDefault("watcher.queue.maxBackoff", "60s")
+	viper.SetDefault("watcher.queue.maxRetries", 10)
+	viper.SetDefault("watcher.leaderElection.enabled", false)
+	viper.SetDefault("watcher.leaderElection.leaseName", "quasar-watcher")
+	viper.SetDefault("watcher.leaderElection.leaseNamespace", "default")
+	viper.SetDefault("watcher.leaderElection.leaseDuration", "15s")
+	viper.SetDefault("watcher.leaderElection.renewDeadline", "10s")
+	viper.SetDefault("watcher.leaderElection.retryPeriod", "2s")
+
+	viper.SetDefault("store.redis.mode", RedisModeStandalone)
 	viper.SetDefault("store.redis.host", "localhost")
 	viper.SetDefault("store.redis.port", 6379)
+	viper.SetDefault("store.redis.addresses", []string{})
 	viper.SetDefault("store.redis.username", "")
 	viper.SetDefault("store.redis.password", "")
 	viper.SetDefault("store.redis.database", 0)
+	viper.SetDefault("store.redis.sentinel.masterName", "")
+	viper.SetDefault("store.redis.sentinel.username", "")
+	viper.SetDefault("store.redis.sentinel.password", "")
+	viper.SetDefault("store.redis.tls.enabled", false)
+	viper.SetDefault("store.redis.tls.caFile", "")
+	viper.SetDefault("store.redis.tls.certFile", "")
+	viper.SetDefault("store.redis.tls.keyFile", "")
+	viper.SetDefault("store.redis.tls.serverName", "")
+	viper.SetDefault("store.redis.tls.insecureSkipVerify", false)
+	viper.SetDefault("store.redis.reconciliationInterval", "60s")
+	viper.SetDefault("store.redis.operationTimeout", "5s")
 
 	viper.SetDefault("store.hazelcast.addresses", []string{})
 	viper.SetDefault("store.hazelcast.clusterName", "horizon")
@@ -65,6 +100,7 @@ func setDefaults() {
 	viper.SetDefault("store.hazelcast.heartbeatTimeout", "30s")
 	viper.SetDefault("store.hazelcast.connectionTimeout", "30s")
 	viper.SetDefault("store.hazelcast.invocationTimeout", "60s")
+	viper.SetDefault("store.hazelcast.operationTimeout", "10s")
 	viper.SetDefault("store.hazelcast.redoOperatiom", false)
 	viper.SetDefault("store.hazelcast.connectionStrategy.timeout", "10m")
 	viper.SetDefault("store.hazelcast.connectionStrategy.retry.initialBackoff", "1s")
@@ -74,6 +110,46 @@ func setDefaults() {
 
 	viper.SetDefault("store.mongo.uri", "mongodb://localhost:27017")
 	viper.SetDefault("store.mongo.database", "horizon-config")
+	viper.SetDefault("store.mongo.operationTimeout", "10s")
+
+	viper.SetDefault("store.memory.snapshotPath", "")
+
+	viper.SetDefault("store.file.path", "quasar.db")
+	viper.SetDefault("store.file.openTimeout", "10s")
+
+	viper.SetDefault("store.postgres.uri", "postgres://localhost:5432/horizon")
+	viper.SetDefault("store.postgres.schema", "public")
+	viper.SetDefault("store.postgres.operationTimeout", "10s")
+
+	viper.SetDefault("store.kafka.brokers", []string{"localhost:9092"})
+	viper.SetDefault("store.kafka.clientId", "quasar")
+	viper.SetDefault("store.kafka.timeout", "10s")
+	viper.SetDefault("store.kafka.tombstones", false)
+
+	viper.SetDefault("store.webhook.url", "")
+	viper.SetDefault("store.webhook.source", "quasar")
+	viper.SetDefault("store.webhook.secret", "")
+	viper.SetDefault("store.webhook.timeout", "10s")
+	viper.SetDefault("store.webhook.retry.maxAttempts", 5)
+	viper.SetDefault("store.webhook.retry.initialBackoff", "1s")
+	viper.SetDefault("store.webhook.retry.maxBackoff", "30s")
+	viper.SetDefault("store.webhook.retry.multiplier", 2.0)
+	viper.SetDefault("store.webhook.operationTimeout", "60s")
+
+	viper.SetDefault("store.retryQueue.capacity", 10000)
+	viper.SetDefault("store.retryQueue.workers", 4)
+	viper.SetDefault("store.retryQueue.maxAttempts", 10)
+	viper.SetDefault("store.retryQueue.initialBackoff", "1s")
+	viper.SetDefault("store.retryQueue.maxBackoff", "60s")
+	viper.SetDefault("store.retryQueue.multiplier", 2.0)
+	viper.SetDefault("store.retryQueue.path", "")
+	viper.SetDefault("store.retryQueue.drainTimeout", "10s")
+
+	viper.SetDefault("store.circuitBreaker.enabled", true)
+	viper.SetDefault("store.circuitBreaker.failureThreshold", 5)
+	viper.SetDefault("store.circuitBreaker.timeout", "10s")
+	viper.SetDefault("store.circuitBreaker.openDuration", "30s")
+	viper.SetDefault("store.circuitBreaker.halfOpenProbes", 1)
 
 	viper.SetDefault("resources", []Resource{})
 
@@ -84,6 +160,11 @@ func setDefaults() {
 	viper.SetDefault("metrics.enabled", false)
 	viper.SetDefault("metrics.port", 8080)
 	viper.SetDefault("metrics.timeout", "5s")
+
+	viper.SetDefault("reconciliation.mode", ReconcileModeIncremental)
+	viper.SetDefault("reconciliation.interval", "60s")
+	viper.SetDefault("reconciliation.deleteOrphans", true)
+	viper.SetDefault("reconciliation.orphanThreshold", 0.5)
 }
 
 func readConfig() *Configuration {
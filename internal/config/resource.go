@@ -6,26 +6,78 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/hazelcast/hazelcast-go-client/types"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type Resource struct {
 	Kubernetes struct {
-		Group     string `mapstructure:"group"`
-		Version   string `mapstructure:"version"`
-		Resource  string `mapstructure:"resource"`
-		Kind      string `mapstructure:"kind"`
-		Namespace string `mapstructure:"namespace"`
+		Group             string   `mapstructure:"group"`
+		Version           string   `mapstructure:"version"`
+		Resource          string   `mapstructure:"resource"`
+		Kind              string   `mapstructure:"kind"`
+		Namespace         string   `mapstructure:"namespace"`
+		Namespaces        []string `mapstructure:"namespaces"`
+		NamespaceSelector string   `mapstructure:"namespaceSelector"`
+		LabelSelector     string   `mapstructure:"labelSelector"`
+		FieldSelector     string   `mapstructure:"fieldSelector"`
 	} `mapstructure:"kubernetes"`
 	MongoId          string                   `mapstructure:"mongoId"`
 	MongoIndexes     []MongoResourceIndex     `mapstructure:"mongoIndexes"`
 	HazelcastIndexes []HazelcastResourceIndex `mapstructure:"hazelcastIndexes"`
+	MemoryIndexes    []string                 `mapstructure:"memoryIndexes"`
+	PostgresIndexes  []PostgresResourceIndex  `mapstructure:"postgresIndexes"`
+	KafkaTopic       string                   `mapstructure:"kafkaTopic"`
+	WebhookUrl       string                   `mapstructure:"webhookUrl"`
 	Prometheus       Prometheus               `mapstructure:"prometheus"`
+	Reconciliation   ResourceReconciliation   `mapstructure:"reconciliation"`
+	Workers          int                      `mapstructure:"workers"`
+}
+
+// ResourceReconciliation overrides the reconciliation settings of the stores for a single resource.
+// Unset fields fall back to the settings of the store or the global reconciliation settings.
+type ResourceReconciliation struct {
+	Enabled       *bool         `mapstructure:"enabled"`
+	Mode          ReconcileMode `mapstructure:"mode"`
+	Interval      time.Duration `mapstructure:"interval"`
+	Jitter        time.Duration `mapstructure:"jitter"`
+	DeleteOrphans *bool         `mapstructure:"deleteOrphans"`
+}
+
+// IsEnabled returns whether the resource should be reconciled at all.
+func (r *ResourceReconciliation) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// GetMode returns the reconcile mode of the resource or the given default if unset.
+func (r *ResourceReconciliation) GetMode(defaultMode ReconcileMode) ReconcileMode {
+	if r.Mode == "" {
+		return defaultMode
+	}
+	return r.Mode
+}
+
+// GetInterval returns the reconciliation interval of the resource or the given default if unset.
+func (r *ResourceReconciliation) GetInterval(defaultInterval time.Duration) time.Duration {
+	if r.Interval <= 0 {
+		return defaultInterval
+	}
+	return r.Interval
+}
+
+// ShouldDeleteOrphans returns whether orphaned entries of the resource should be removed or the given default if unset.
+func (r *ResourceReconciliation) ShouldDeleteOrphans(defaultDeleteOrphans bool) bool {
+	if r.DeleteOrphans == nil {
+		return defaultDeleteOrphans
+	}
+	return *r.DeleteOrphans
 }
 
 func (c *Resource) GetGroupVersionResource() schema.GroupVersionResource {
@@ -50,6 +102,41 @@ func (c *Resource) GetGroupVersionName() string {
 	return strings.ToLower(name)
 }
 
+// GetNamespaces returns the namespaces the resource is watched in, apart from the ones matching the namespace selector.
+// Without namespaces and namespace selector, this is the single namespace of the resource, where empty stands for all.
+func (c *Resource) GetNamespaces() []string {
+	if !c.HasMultipleNamespaces() {
+		return []string{c.Kubernetes.Namespace}
+	}
+
+	namespaces := slices.Clone(c.Kubernetes.Namespaces)
+	if c.Kubernetes.Namespace != "" {
+		namespaces = append(namespaces, c.Kubernetes.Namespace)
+	}
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// HasMultipleNamespaces returns whether the resource is watched in a list of namespaces or the namespaces matching
+// a namespace selector instead of a single namespace.
+func (c *Resource) HasMultipleNamespaces() bool {
+	return len(c.Kubernetes.Namespaces) > 0 || c.Kubernetes.NamespaceSelector != ""
+}
+
+// ApplySelectors restricts the list and watch options to the resources matching the selectors of the resource.
+func (c *Resource) ApplySelectors(options *v1.ListOptions) {
+	options.LabelSelector = c.Kubernetes.LabelSelector
+	options.FieldSelector = c.Kubernetes.FieldSelector
+}
+
+// GetWorkers returns the number of workers writing the events of the resource or the given default if unset.
+func (c *Resource) GetWorkers(defaultWorkers int) int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return max(defaultWorkers, 1)
+}
+
 type MongoResourceIndex map[string]int
 
 func (i MongoResourceIndex) ToIndexModel() mongo.IndexModel {
@@ -87,3 +174,9 @@ func (i *HazelcastResourceIndex) ToIndexConfig() types.IndexConfig {
 		Type:       i.translateIndexType(),
 	}
 }
+
+type PostgresResourceIndex struct {
+	Name   string   `mapstructure:"name"`
+	Fields []string `mapstructure:"fields"`
+	Unique bool     `mapstructure:"unique"`
+}
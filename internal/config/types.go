@@ -28,3 +28,34 @@ const (
 	ModeProvisioning Mode = "provisioning"
 	ModeWatcher      Mode = "watcher"
 )
+
+type RedisMode string
+
+const (
+	RedisModeStandalone RedisMode = "standalone"
+	RedisModeSentinel   RedisMode = "sentinel"
+	RedisModeCluster    RedisMode = "cluster"
+)
+
+type StoreRole string
+
+const (
+	StoreRoleAuthoritative StoreRole = "authoritative"
+	StoreRoleReplica       StoreRole = "replica"
+	StoreRoleSink          StoreRole = "sink"
+)
+
+type StoreWritePolicy string
+
+const (
+	StoreWritePolicySync  StoreWritePolicy = "sync"
+	StoreWritePolicyAsync StoreWritePolicy = "async"
+)
+
+type WriteConsistency string
+
+const (
+	WriteConsistencyPrimaryOnlyAck WriteConsistency = "primary-only-ack"
+	WriteConsistencyAllAck         WriteConsistency = "all-ack"
+	WriteConsistencyAnyAck         WriteConsistency = "any-ack"
+)
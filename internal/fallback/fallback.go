@@ -5,6 +5,7 @@
 package fallback
 
 import (
+	"context"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -15,11 +16,11 @@ import (
 
 var CurrentFallback Fallback
 
-type ReplayFunc func(obj *unstructured.Unstructured) error
+type ReplayFunc func(ctx context.Context, obj *unstructured.Unstructured) error
 
 type Fallback interface {
 	Initialize()
-	ReplayResource(gvr *schema.GroupVersionResource, replayFunc ReplayFunc) (int64, error)
+	ReplayResource(ctx context.Context, gvr *schema.GroupVersionResource, replayFunc ReplayFunc) (int64, error)
 }
 
 func SetupFallback() {
@@ -37,9 +37,7 @@ func (m *MongoFallback) Initialize() {
 	}
 }
 
-func (m *MongoFallback) ReplayResource(gvr *schema.GroupVersionResource, replayFunc ReplayFunc) (int64, error) {
-	ctx := context.Background()
-
+func (m *MongoFallback) ReplayResource(ctx context.Context, gvr *schema.GroupVersionResource, replayFunc ReplayFunc) (int64, error) {
 	col := m.getCollection(gvr)
 	count, err := col.EstimatedDocumentCount(ctx)
 	if err != nil {
@@ -68,7 +66,7 @@ func (m *MongoFallback) ReplayResource(gvr *schema.GroupVersionResource, replayF
 		var unstructuredObj unstructured.Unstructured
 		_ = unstructuredObj.UnmarshalJSON(bytes)
 
-		err := replayFunc(&unstructuredObj)
+		err := replayFunc(ctx, &unstructuredObj)
 		if err != nil {
 			return 0, err
 		}
@@ -0,0 +1,128 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"context"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/utils"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// leaderGauge is set to 1 while this instance is the leader of the watchers.
+const leaderGauge = "watcher_leader"
+
+// startLeaderElection lets the watchers write to the store only while this instance holds the lease.
+// The informers of all instances keep running, so that a follower can take over right away.
+func startLeaderElection(kubeConfigPath string, watchers []*ResourceWatcher) error {
+	client, err := createLeaseClient(kubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	identity, ok := os.LookupEnv("POD_NAME")
+	if !ok {
+		identity = "quasar-" + uuid.New().String()
+	}
+
+	// the elector validates the configuration, so it is created before starting, to fail instead of never leading
+	elector, err := newLeaderElector(client, identity, watchers)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runLeaderElection(ctx, elector)
+		close(done)
+	}()
+
+	// release the lease after the watchers have stopped, so that a follower can take over right away
+	utils.RegisterShutdownHook(func() {
+		cancel()
+		<-done
+	}, 1)
+	return nil
+}
+
+// runLeaderElection takes part in the election until the context is done. An instance that loses the lease steps
+// down to a follower and runs for the lease again.
+func runLeaderElection(ctx context.Context, elector *leaderelection.LeaderElector) {
+	for ctx.Err() == nil {
+		elector.Run(ctx)
+	}
+}
+
+// newLeaderElector creates an elector for the configured lease. As the writes of a former leader can not be fenced,
+// an instance that loses the lease cancels its reconciliation and the writes in progress before following again.
+func newLeaderElector(
+	client coordinationv1.LeasesGetter,
+	identity string,
+	watchers []*ResourceWatcher,
+) (*leaderelection.LeaderElector, error) {
+	electionConfig := config.Current.Watcher.LeaderElection
+	metrics.GetOrCreateCustom(leaderGauge).WithLabelValues().Set(0)
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: v1.ObjectMeta{
+			Name:      electionConfig.LeaseName,
+			Namespace: electionConfig.LeaseNamespace,
+		},
+		Client:     client,
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   electionConfig.LeaseDuration,
+		RenewDeadline:   electionConfig.RenewDeadline,
+		RetryPeriod:     electionConfig.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            electionConfig.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				log.Info().Str("identity", identity).Msg("Started leading, writing to the watcher store")
+				metrics.GetOrCreateCustom(leaderGauge).WithLabelValues().Set(1)
+				for _, watcher := range watchers {
+					watcher.lead()
+				}
+			},
+			OnStoppedLeading: func() {
+				metrics.GetOrCreateCustom(leaderGauge).WithLabelValues().Set(0)
+				if !isLeading(watchers) {
+					return
+				}
+
+				for _, watcher := range watchers {
+					watcher.follow()
+				}
+				log.Warn().Str("identity", identity).Msg("Lost leadership, following until elected again")
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					log.Info().Str("leader", leader).Msg("Following the watcher leader")
+				}
+			},
+		},
+	})
+}
+
+func isLeading(watchers []*ResourceWatcher) bool {
+	for _, watcher := range watchers {
+		if watcher.leading.Load() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/reconciliation"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespacedInformer watches the resource in a single namespace.
+type namespacedInformer struct {
+	informer  cache.SharedIndexInformer
+	namespace string
+	stopChan  chan struct{}
+	// static informers watch configured namespaces and are kept if the namespace stops matching the selector
+	static bool
+	// failed is whether the informer has encountered an error before being in sync while leading
+	failed atomic.Bool
+}
+
+// newNamespacedInformer creates an informer that queues the events of the resource in the namespace.
+func (w *ResourceWatcher) newNamespacedInformer(namespace string, static bool) (*namespacedInformer, error) {
+	resource := w.resourceConfig.GetGroupVersionResource()
+	informer := &namespacedInformer{
+		informer:  createInformer(w.client, resource, namespace, w.reSyncPeriod, w.resourceConfig.ApplySelectors),
+		namespace: namespace,
+		stopChan:  make(chan struct{}),
+		static:    static,
+	}
+
+	err := informer.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		w.handleWatchError(informer, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = informer.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.add,
+		UpdateFunc: w.update,
+		DeleteFunc: w.delete,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return informer, nil
+}
+
+// newNamespaceInformer creates an informer that starts and stops watching namespaces as they start and stop
+// matching the namespace selector of the resource.
+func (w *ResourceWatcher) newNamespaceInformer() (cache.SharedIndexInformer, error) {
+	informer := createInformer(w.client, reconciliation.NamespaceResource, "", w.reSyncPeriod, func(options *v1.ListOptions) {
+		options.LabelSelector = w.resourceConfig.Kubernetes.NamespaceSelector
+	})
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if namespace, ok := obj.(*unstructured.Unstructured); ok {
+				w.watchNamespace(namespace.GetName())
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown); isTombstone {
+				obj = tombstone.Obj
+			}
+			if namespace, ok := obj.(*unstructured.Unstructured); ok {
+				w.unwatchNamespace(namespace.GetName())
+			}
+		},
+	})
+	return informer, err
+}
+
+// watchNamespace starts an informer for the namespace unless it is watched already.
+func (w *ResourceWatcher) watchNamespace(namespace string) {
+	w.informersMu.Lock()
+	defer w.informersMu.Unlock()
+
+	if _, ok := w.informers[namespace]; ok || w.stopped {
+		return
+	}
+
+	informer, err := w.newNamespacedInformer(namespace, false)
+	if err != nil {
+		log.Error().Err(err).Fields(w.namespaceFields(namespace)).Msg("Could not watch namespace")
+		return
+	}
+	w.informers[namespace] = informer
+	go runInformer(informer.informer, informer.stopChan)
+
+	log.Info().Fields(w.namespaceFields(namespace)).Msg("Started watching namespace")
+}
+
+// unwatchNamespace stops the informer of a namespace that does not match the namespace selector anymore.
+// Its resources are removed from the store by the next reconciliation.
+func (w *ResourceWatcher) unwatchNamespace(namespace string) {
+	w.informersMu.Lock()
+	informer, ok := w.informers[namespace]
+	w.informersMu.Unlock()
+
+	if ok {
+		w.stopWatching(informer)
+	}
+}
+
+// stopWatching stops the informer of a selected namespace unless it has been stopped or replaced already.
+// The namespace is watched again once it is selected again.
+func (w *ResourceWatcher) stopWatching(informer *namespacedInformer) {
+	w.informersMu.Lock()
+	defer w.informersMu.Unlock()
+
+	if w.informers[informer.namespace] != informer || informer.static || w.stopped {
+		return
+	}
+	close(informer.stopChan)
+	delete(w.informers, informer.namespace)
+	w.forgetNamespace(informer.namespace)
+
+	log.Info().Fields(w.namespaceFields(informer.namespace)).Msg("Stopped watching namespace")
+}
+
+// hasSynced returns whether the informers of all watched namespaces have synced.
+func (w *ResourceWatcher) hasSynced() bool {
+	if w.namespaceInformer != nil && !w.namespaceInformer.HasSynced() {
+		return false
+	}
+
+	w.informersMu.Lock()
+	defer w.informersMu.Unlock()
+
+	for _, informer := range w.informers {
+		if !informer.informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *ResourceWatcher) namespaceFields(namespace string) map[string]any {
+	return map[string]any{
+		"resource":  w.resourceConfig.GetGroupVersionName(),
+		"namespace": namespace,
+	}
+}
+
+func runInformer(informer cache.SharedIndexInformer, stopChan <-chan struct{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Panic().Fields(map[string]any{
+				"error": fmt.Sprintf("%+v", err),
+			}).Msg("Informer failed!")
+		}
+	}()
+	informer.Run(stopChan)
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// observedState is the latest state of a resource observed by the informer that has not been written yet.
+type observedState struct {
+	obj     *unstructured.Unstructured
+	deleted bool
+}
+
+// createQueue creates the queue of resource keys whose latest state has to be written to the store.
+// The queue deduplicates keys and never hands out a key to a worker while another one is processing it.
+func createQueue(resourceConfig *config.Resource) workqueue.TypedRateLimitingInterface[string] {
+	queueConfig := config.Current.Watcher.Queue
+	return workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[string](queueConfig.InitialBackoff, queueConfig.MaxBackoff),
+		workqueue.TypedRateLimitingQueueConfig[string]{
+			Name:            resourceConfig.GetGroupVersionName(),
+			MetricsProvider: metrics.WorkqueueMetricsProvider,
+		},
+	)
+}
+
+// enqueue records the observed state of the resource and queues its key for writing.
+func (w *ResourceWatcher) enqueue(obj *unstructured.Unstructured, deleted bool) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Warn().Err(err).Fields(utils.CreateFieldsForOp("enqueue", obj)).Msg("Could not determine key of object")
+		return
+	}
+
+	w.mu.Lock()
+	w.observed[key] = observedState{obj: obj, deleted: deleted}
+	w.mu.Unlock()
+	w.queue.Add(key)
+}
+
+func (w *ResourceWatcher) runWorker() {
+	for w.processNextKey() {
+	}
+}
+
+// processNextKey writes the latest observed state of the next queued resource to the store. Failed writes are
+// retried with exponential backoff until the maximum number of retries has been reached.
+func (w *ResourceWatcher) processNextKey() bool {
+	key, shutdown := w.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer w.queue.Done(key)
+
+	w.mu.Lock()
+	state, ok := w.observed[key]
+	written := w.written[key]
+	ctx := w.leaderCtx
+	w.mu.Unlock()
+	if !ok || ctx == nil {
+		w.queue.Forget(key)
+		return true
+	}
+
+	written, err := w.write(ctx, state, written)
+	if err != nil && ctx.Err() != nil {
+		// leadership has been lost or the watcher has been stopped, so the write is left to the next leader
+		w.queue.Forget(key)
+		return true
+	}
+	if err != nil {
+		maxRetries := config.Current.Watcher.Queue.MaxRetries
+		if maxRetries <= 0 || w.queue.NumRequeues(key) < maxRetries {
+			log.Warn().Err(err).Fields(utils.CreateFieldsForOp("write", state.obj)).Msg("Could not write dataset, retrying")
+			w.queue.AddRateLimited(key)
+			return true
+		}
+		log.Error().Err(err).Fields(utils.CreateFieldsForOp("write", state.obj)).
+			Msg("Could not write dataset, giving up until the next reconciliation")
+	}
+
+	w.mu.Lock()
+	// resources that have been forgotten while being written, as leadership has been lost or their namespace is not
+	// watched anymore, are not recorded as written
+	if observed, ok := w.observed[key]; ok {
+		if err == nil {
+			w.record(key, state, written)
+		}
+		if observed == state {
+			delete(w.observed, key)
+		}
+	}
+	w.mu.Unlock()
+
+	w.queue.Forget(key)
+	return true
+}
+
+// write applies the observed state to the store, based on the state that has been written last, and returns the
+// state that has been written. The object of the informer cache is not modified.
+func (w *ResourceWatcher) write(
+	ctx context.Context,
+	state observedState,
+	written *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	if state.deleted {
+		if err := WatcherStore.Delete(ctx, state.obj); err != nil {
+			return nil, err
+		}
+		log.Debug().Fields(utils.CreateFieldsForOp("delete", state.obj)).Msg("Deleted dataset")
+		return nil, nil
+	}
+
+	if written != nil && written.GetResourceVersion() == state.obj.GetResourceVersion() {
+		return written, nil
+	}
+
+	uObj := withEnvironment(state.obj)
+	if written == nil {
+		if err := WatcherStore.Create(ctx, uObj); err != nil {
+			return nil, err
+		}
+		log.Debug().Fields(utils.CreateFieldsForOp("add", uObj)).Msg("Added dataset")
+		return uObj, nil
+	}
+
+	if err := WatcherStore.Update(ctx, written, uObj); err != nil {
+		return nil, err
+	}
+	log.Debug().Fields(utils.CreateFieldsForOp("update", written)).Msg("Updated dataset")
+	return uObj, nil
+}
+
+// record records the state that has been written and counts the resources added to and deleted from the store.
+// It has to be called with the lock of the watcher held.
+func (w *ResourceWatcher) record(key string, state observedState, written *unstructured.Unstructured) {
+	if state.deleted {
+		w.count(state.obj, -1)
+		delete(w.written, key)
+		return
+	}
+
+	if _, ok := w.written[key]; !ok {
+		w.count(written, 1)
+	}
+	w.written[key] = written
+}
+
+// seedWritten marks the resources in the informer caches as written when taking over leadership, as the previous
+// leader has written them already. Their next changes are then applied as updates, while the changes the previous
+// leader has missed are repaired by the reconciliation on takeover.
+func (w *ResourceWatcher) seedWritten() {
+	cached := make([]*unstructured.Unstructured, 0)
+	w.informersMu.Lock()
+	for _, informer := range w.informers {
+		for _, obj := range informer.informer.GetStore().List() {
+			if uObj, ok := obj.(*unstructured.Unstructured); ok {
+				cached = append(cached, uObj)
+			}
+		}
+	}
+	w.informersMu.Unlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, uObj := range cached {
+		key, err := cache.MetaNamespaceKeyFunc(uObj)
+		if err != nil {
+			continue
+		}
+		if _, ok := w.written[key]; !ok {
+			w.written[key] = withEnvironment(uObj)
+			w.count(uObj, 1)
+		}
+	}
+}
+
+// resetWritten forgets the resources that have been written while leading, as the next leader writes them.
+// It has to be called with the lock of the watcher held.
+func (w *ResourceWatcher) resetWritten() {
+	for key, uObj := range w.written {
+		w.count(uObj, -1)
+		delete(w.written, key)
+	}
+}
+
+// forgetNamespace forgets the observed and written resources of a namespace that is not watched anymore, as they are
+// removed from the store by the next reconciliation.
+func (w *ResourceWatcher) forgetNamespace(namespace string) {
+	prefix := namespace + "/"
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key := range w.observed {
+		if strings.HasPrefix(key, prefix) {
+			delete(w.observed, key)
+		}
+	}
+	for key, uObj := range w.written {
+		if strings.HasPrefix(key, prefix) {
+			w.count(uObj, -1)
+			delete(w.written, key)
+		}
+	}
+}
+
+// count adds delta to the gauge of the resource if metrics are enabled for it.
+func (w *ResourceWatcher) count(obj *unstructured.Unstructured, delta float64) {
+	if config.Current.Metrics.Enabled && w.resourceConfig.Prometheus.Enabled {
+		labels := utils.GetLabelsForResource(obj, w.resourceConfig)
+		metrics.GetOrCreate(w.resourceConfig).With(labels).Add(delta)
+	}
+}
+
+// withEnvironment returns a copy of the object that defaults its environment, leaving the informer cache untouched.
+func withEnvironment(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	uObj := obj.DeepCopy()
+	utils.AddMissingEnvironment(uObj)
+	return uObj
+}
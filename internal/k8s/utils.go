@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
@@ -43,12 +44,30 @@ func CreateKubeConfigClient(kubeConfigPath string) (*dynamic.DynamicClient, erro
 	return client, nil
 }
 
+// createLeaseClient creates a client for the leases used by the leader election, using the service account if
+// no kubeconfig is given.
+func createLeaseClient(kubeConfigPath string) (coordinationv1.LeasesGetter, error) {
+	var config *rest.Config
+	var err error
+	if len(kubeConfigPath) == 0 {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return coordinationv1.NewForConfig(config)
+}
+
 func createInformer(
 	client dynamic.Interface,
 	resource schema.GroupVersionResource,
 	namespace string,
 	reSyncPeriod time.Duration,
+	tweakListOptions dynamicinformer.TweakListOptionsFunc,
 ) cache.SharedIndexInformer {
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, reSyncPeriod, namespace, nil)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, reSyncPeriod, namespace, tweakListOptions)
 	return factory.ForResource(resource).Informer()
 }
@@ -6,7 +6,10 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -16,19 +19,34 @@ import (
 	"github.com/telekom/quasar/internal/reconciliation"
 	"github.com/telekom/quasar/internal/store"
 	"github.com/telekom/quasar/internal/utils"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 )
 
 var WatcherStore store.Store
 
 type ResourceWatcher struct {
-	client         dynamic.Interface
-	resourceConfig *config.Resource
-	informer       cache.SharedIndexInformer
-	stopChan       chan struct{}
+	client            dynamic.Interface
+	resourceConfig    *config.Resource
+	reSyncPeriod      time.Duration
+	informersMu       sync.Mutex
+	informers         map[string]*namespacedInformer
+	namespaceInformer cache.SharedIndexInformer
+	stopped           bool
+	replayed          atomic.Bool
+	initialized       sync.Once
+	stopChan          chan struct{}
+	ctx               context.Context
+	cancel            context.CancelFunc
+	leading           atomic.Bool
+	queue             workqueue.TypedRateLimitingInterface[string]
+	mu                sync.Mutex
+	leaderCtx         context.Context
+	stopLeading       context.CancelFunc
+	observed          map[string]observedState
+	written           map[string]*unstructured.Unstructured
 }
 
 func SetupWatchers(kubeConfigPath string) {
@@ -52,6 +70,7 @@ func SetupWatchers(kubeConfigPath string) {
 		}
 	}
 
+	watchers := make([]*ResourceWatcher, 0, len(config.Current.Resources))
 	for _, resourceConfig := range config.Current.Resources {
 		watcher, err := NewResourceWatcher(kubernetesClient, &resourceConfig, config.Current.ReSyncPeriod)
 		if err != nil {
@@ -60,6 +79,14 @@ func SetupWatchers(kubeConfigPath string) {
 		}
 		go watcher.Start()
 		utils.RegisterShutdownHook(watcher.Stop, 0)
+		watchers = append(watchers, watcher)
+	}
+
+	if config.Current.Watcher.LeaderElection.Enabled {
+		if err := startLeaderElection(kubeConfigPath, watchers); err != nil {
+			log.Error().Err(err).Msg("Could not start leader election!")
+			utils.GracefulShutdown()
+		}
 	}
 }
 
@@ -68,65 +95,91 @@ func NewResourceWatcher(
 	resourceConfig *config.Resource,
 	reSyncPeriod time.Duration,
 ) (*ResourceWatcher, error) {
-	resource := resourceConfig.GetGroupVersionResource()
-	namespace := resourceConfig.Kubernetes.Namespace
-	informer := createInformer(client, resource, namespace, reSyncPeriod)
-	watcher := ResourceWatcher{
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := &ResourceWatcher{
 		client:         client,
 		resourceConfig: resourceConfig,
-		informer:       informer,
+		reSyncPeriod:   reSyncPeriod,
+		informers:      make(map[string]*namespacedInformer),
 		stopChan:       make(chan struct{}),
+		ctx:            ctx,
+		cancel:         cancel,
+		queue:          createQueue(resourceConfig),
+		observed:       make(map[string]observedState),
+		written:        make(map[string]*unstructured.Unstructured),
 	}
 
-	performReplay := true
-	err := informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
-		if !informer.HasSynced() && performReplay {
-			performReplay = false
-			log.Info().Msg("The informer encountered an error before being in sync. Falling back to MongoDB...")
-
-			resource := resourceConfig.GetGroupVersionResource()
-
-			replayedDocuments, err := fallback.CurrentFallback.ReplayResource(&resource, WatcherStore.Create)
-			if err != nil {
-				log.Fatal().Err(err).Msg("Replay from MongoDB failed!")
-			}
-			log.Info().Fields(map[string]any{
-				"replayedDocuments": replayedDocuments,
-			}).Msg("Replay from MongoDB successful!")
-		} else {
-			log.Fatal().Err(err).Msg("Watcher failed. Terminating...")
+	for _, namespace := range resourceConfig.GetNamespaces() {
+		informer, err := watcher.newNamespacedInformer(namespace, true)
+		if err != nil {
+			return nil, err
 		}
-	})
-	if err != nil {
-		return nil, err
+		watcher.informers[namespace] = informer
 	}
 
-	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    watcher.add,
-		UpdateFunc: watcher.update,
-		DeleteFunc: watcher.delete,
-	})
+	if resourceConfig.Kubernetes.NamespaceSelector != "" {
+		namespaceInformer, err := watcher.newNamespaceInformer()
+		if err != nil {
+			return nil, err
+		}
+		watcher.namespaceInformer = namespaceInformer
+	}
 
 	go watcher.collectMetrics(client, resourceConfig)
 
-	return &watcher, err
+	return watcher, nil
 }
 
-func (w *ResourceWatcher) add(obj any) {
-	uObj, ok := obj.(*unstructured.Unstructured)
-	if ok {
-		utils.AddMissingEnvironment(uObj)
-		err := WatcherStore.Create(uObj)
+// handleWatchError handles an error of the informer of a namespace. An informer that fails before being in sync falls
+// back to replaying the resource, which is done once for all namespaces and only by the leader. An informer of a
+// selected namespace that fails before being in sync, e.g. as the namespace must not be listed, stops watching it.
+// Any other error terminates the watcher.
+func (w *ResourceWatcher) handleWatchError(informer *namespacedInformer, err error) {
+	fields := w.namespaceFields(informer.namespace)
+
+	switch {
+	case informer.informer.HasSynced():
+		log.Fatal().Err(err).Fields(fields).Msg("Watcher failed. Terminating...")
+
+	case !informer.static:
+		log.Error().Err(err).Fields(fields).
+			Msg("The informer encountered an error before being in sync. Stopping to watch the namespace...")
+		w.stopWatching(informer)
+
+	case !w.leading.Load():
+		log.Warn().Err(err).Fields(fields).
+			Msg("The informer encountered an error before being in sync. Skipping replay as follower...")
+
+	case !informer.failed.CompareAndSwap(false, true):
+		log.Fatal().Err(err).Fields(fields).Msg("Watcher failed. Terminating...")
+
+	case w.replayed.CompareAndSwap(false, true):
+		log.Info().Fields(fields).Msg("The informer encountered an error before being in sync. Falling back to MongoDB...")
+
+		resource := w.resourceConfig.GetGroupVersionResource()
+
+		replayedDocuments, err := fallback.CurrentFallback.ReplayResource(w.ctx, &resource, WatcherStore.Create)
 		if err != nil {
-			return
+			log.Fatal().Err(err).Msg("Replay from MongoDB failed!")
 		}
+		log.Info().Fields(map[string]any{
+			"replayedDocuments": replayedDocuments,
+		}).Msg("Replay from MongoDB successful!")
 
-		if config.Current.Metrics.Enabled && w.resourceConfig.Prometheus.Enabled {
-			labels := utils.GetLabelsForResource(uObj, w.resourceConfig)
-			metrics.GetOrCreate(w.resourceConfig).With(labels).Inc()
-		}
+	default:
+		log.Warn().Err(err).Fields(fields).
+			Msg("The informer encountered an error before being in sync. Resource has been replayed already...")
+	}
+}
+
+func (w *ResourceWatcher) add(obj any) {
+	if !w.leading.Load() {
+		return
+	}
 
-		log.Debug().Fields(utils.CreateFieldsForOp("add", uObj)).Msg("Added dataset")
+	uObj, ok := obj.(*unstructured.Unstructured)
+	if ok {
+		w.enqueue(uObj, false)
 	} else {
 		log.Warn().Fields(map[string]any{
 			"object":    fmt.Sprintf("%+v", obj),
@@ -136,19 +189,17 @@ func (w *ResourceWatcher) add(obj any) {
 }
 
 func (w *ResourceWatcher) update(oldObj any, newObj any) {
+	if !w.leading.Load() {
+		return
+	}
+
 	uOldObj, oldOk := oldObj.(*unstructured.Unstructured)
 	uNewObj, newOk := newObj.(*unstructured.Unstructured)
 	if oldOk && newOk {
 		if uNewObj.GetResourceVersion() == uOldObj.GetResourceVersion() {
 			return
 		}
-
-		utils.AddMissingEnvironment(uNewObj)
-		err := WatcherStore.Update(uOldObj, uNewObj)
-		if err != nil {
-			return
-		}
-		log.Debug().Fields(utils.CreateFieldsForOp("update", uOldObj)).Msg("Updated dataset")
+		w.enqueue(uNewObj, false)
 	} else {
 		log.Warn().Fields(map[string]any{
 			"oldObject": fmt.Sprintf("%+v", uOldObj),
@@ -159,18 +210,21 @@ func (w *ResourceWatcher) update(oldObj any, newObj any) {
 }
 
 func (w *ResourceWatcher) delete(obj any) {
+	if !w.leading.Load() {
+		return
+	}
+
+	// deletions that have been missed by the watch are only noticed when relisting and come as tombstones
+	if tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown); isTombstone {
+		log.Debug().Fields(map[string]any{
+			"key": tombstone.Key,
+		}).Msg("Encountered tombstone of missed deletion in informer")
+		obj = tombstone.Obj
+	}
+
 	uObj, ok := obj.(*unstructured.Unstructured)
 	if ok {
-		err := WatcherStore.Delete(uObj)
-		if err != nil {
-			return
-		}
-		log.Debug().Fields(utils.CreateFieldsForOp("delete", uObj)).Msg("Deleted dataset")
-
-		if config.Current.Metrics.Enabled && w.resourceConfig.Prometheus.Enabled {
-			labels := utils.GetLabelsForResource(uObj, w.resourceConfig)
-			metrics.GetOrCreate(w.resourceConfig).With(labels).Dec()
-		}
+		w.enqueue(uObj, true)
 	} else {
 		log.Warn().Fields(map[string]any{
 			"object":    fmt.Sprintf("%+v", obj),
@@ -179,25 +233,103 @@ func (w *ResourceWatcher) delete(obj any) {
 	}
 }
 
+// Start runs the informers and the workers of the watcher until it is stopped. Without leader election the watcher
+// writes to the store right away, otherwise only once this instance has been elected.
 func (w *ResourceWatcher) Start() {
-	reconciliationSource := reconciliation.NewDataSourceFromKubernetesClient(w.client, w.resourceConfig)
-	WatcherStore.InitializeResource(reconciliationSource, w.resourceConfig)
+	if !config.Current.Watcher.LeaderElection.Enabled {
+		w.lead()
+	}
 
-	defer func() {
-		if err := recover(); err != nil {
-			log.Panic().Fields(map[string]any{
-				"error": fmt.Sprintf("%+v", err),
-			}).Msg("Informer failed!")
-		}
-	}()
-	w.informer.Run(w.stopChan)
+	for range w.resourceConfig.GetWorkers(config.Current.Watcher.Queue.Workers) {
+		go w.runWorker()
+	}
+
+	w.informersMu.Lock()
+	for _, informer := range w.informers {
+		go runInformer(informer.informer, informer.stopChan)
+	}
+	w.informersMu.Unlock()
+
+	if w.namespaceInformer != nil {
+		go runInformer(w.namespaceInformer, w.stopChan)
+	}
+	<-w.stopChan
 
 	resource := w.resourceConfig.GetGroupVersionResource()
 	log.Info().Fields(utils.CreateFieldForResource(&resource)).Msg("Resource watcher stopped!")
 }
 
+// lead initializes the resource in the store and lets the watcher write to it. With leader election, the resource is
+// reconciled right away, as events have not been written while following, and periodically until leadership is lost.
+func (w *ResourceWatcher) lead() {
+	reconciliationSource := reconciliation.NewDataSourceFromKubernetesClient(w.client, w.resourceConfig)
+	reconciler, reconciled := WatcherStore.(store.LeaderReconciler)
+	reconciled = reconciled && config.Current.Watcher.LeaderElection.Enabled
+
+	w.initialized.Do(func() {
+		if reconciled {
+			reconciler.InitializeStores(reconciliationSource, w.resourceConfig)
+		} else {
+			WatcherStore.InitializeResource(reconciliationSource, w.resourceConfig)
+		}
+	})
+
+	w.mu.Lock()
+	if w.stopLeading != nil {
+		w.stopLeading()
+	}
+	ctx, cancel := context.WithCancel(w.ctx)
+	if config.Current.Watcher.LeaderElection.Enabled {
+		// writes still queued for asynchronous stores must not be applied once another instance leads
+		ctx = store.BindAsyncWrites(ctx)
+	}
+	w.leaderCtx, w.stopLeading = ctx, cancel
+	w.mu.Unlock()
+	w.seedWritten()
+	w.leading.Store(true)
+
+	if reconciled {
+		reconciler.StartReconciliation(ctx, reconciliationSource, w.resourceConfig)
+		go func() {
+			_, err := reconciler.Reconcile(ctx, reconciliationSource, w.resourceConfig)
+			if err != nil && ctx.Err() == nil && !errors.Is(err, reconciliation.ErrReconciliationDisabled) {
+				log.Error().Err(err).Fields(map[string]any{
+					"resource": w.resourceConfig.GetGroupVersionName(),
+				}).Msg("Could not reconcile resource after taking over leadership")
+			}
+		}()
+	}
+}
+
+// follow stops the watcher from writing to the store. The reconciliation and the writes in progress are cancelled,
+// queued events and writes queued for asynchronous stores are dropped, as the next leader takes care of them and of
+// the resources written so far. The informers keep running, so that the watcher can take over again right away.
+func (w *ResourceWatcher) follow() {
+	w.leading.Store(false)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopLeading != nil {
+		w.stopLeading()
+	}
+	w.leaderCtx, w.stopLeading = nil, nil
+	clear(w.observed)
+	w.resetWritten()
+}
+
 func (w *ResourceWatcher) Stop() {
+	w.follow()
+	w.queue.ShutDown()
+
+	w.informersMu.Lock()
+	w.stopped = true
+	for _, informer := range w.informers {
+		close(informer.stopChan)
+	}
+	w.informersMu.Unlock()
+
 	close(w.stopChan)
+	w.cancel()
 }
 
 func (w *ResourceWatcher) collectMetrics(client dynamic.Interface, resourceConfig *config.Resource) {
@@ -207,10 +339,9 @@ func (w *ResourceWatcher) collectMetrics(client dynamic.Interface, resourceConfi
 		}
 	}()
 
+	dataSource := reconciliation.NewDataSourceFromKubernetesClient(client, resourceConfig)
 	for {
-		list, err := client.Resource(resourceConfig.GetGroupVersionResource()).
-			Namespace(resourceConfig.Kubernetes.Namespace).
-			List(context.Background(), v1.ListOptions{})
+		resources, err := dataSource.ListResources(w.ctx)
 		if err != nil {
 			log.Error().Err(err).Fields(map[string]any{
 				"resource": resourceConfig.GetGroupVersionName(),
@@ -221,21 +352,17 @@ func (w *ResourceWatcher) collectMetrics(client dynamic.Interface, resourceConfi
 		}
 
 		gaugeName := resourceConfig.GetGroupVersionName() + "_kubernetes_count"
-		metrics.GetOrCreateCustom(gaugeName).WithLabelValues().Set(float64(len(list.Items)))
+		metrics.GetOrCreateCustom(gaugeName).WithLabelValues().Set(float64(len(resources)))
 		time.Sleep(15 * time.Second)
 	}
 }
 
 func SetupWatcherStore() {
-	primaryType := config.Current.Watcher.Store.Primary.Type
-	secondaryType := config.Current.Watcher.Store.Secondary.Type
-
 	var err error
-	WatcherStore, err = store.SetupDualStoreManager("WatcherStore", primaryType, secondaryType)
+	WatcherStore, err = store.SetupStoreManager("WatcherStore", &config.Current.Watcher.Store)
 	if err != nil {
 		log.Fatal().Fields(map[string]any{
-			"primaryType":   primaryType,
-			"secondaryType": secondaryType,
+			"stores": config.Current.Watcher.Store.GetStores(),
 		}).Err(err).Msg("Could not create k8s watcher store manager!")
 	}
 }
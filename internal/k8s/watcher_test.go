@@ -6,21 +6,32 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/reconciliation"
 	"github.com/telekom/quasar/internal/test"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var (
@@ -136,3 +147,508 @@ func TestResourceWatcher_Stop(t *testing.T) {
 		"found unexpected warnings, errors and/or panics in the logs",
 	)
 }
+
+func TestResourceWatcher_Follow(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	config.Current.Watcher.LeaderElection.Enabled = true
+	defer func() { config.Current.Watcher.LeaderElection.Enabled = false }()
+	dummyStore := new(test.DummyStore)
+	WatcherStore = dummyStore
+
+	client := createFakeClient()
+	follower, err := NewResourceWatcher(client, &config.Current.Resources[0], 30*time.Second)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go follower.Start()
+	defer follower.Stop()
+	time.Sleep(3 * time.Second)
+
+	assertions.False(dummyStore.HasInitializedResource, "followers should not initialize the resource")
+	assertions.Zero(dummyStore.AddCalls, "followers should not write to the store")
+
+	follower.lead()
+	assertions.True(dummyStore.HasInitializedResource, "leaders should initialize the resource")
+
+	gvr := config.Current.Resources[0].GetGroupVersionResource()
+	_ = client.Resource(gvr).Namespace("playground").Delete(context.Background(), subscriptions[0].GetName(), v1.DeleteOptions{})
+	time.Sleep(1 * time.Second)
+	assertions.Equal(1, dummyStore.DeleteCalls, "leaders should write to the store")
+}
+
+func TestLeaderElection(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	config.Current.Watcher.LeaderElection = config.LeaderElection{
+		Enabled:        true,
+		LeaseName:      "quasar-watcher",
+		LeaseNamespace: "playground",
+		LeaseDuration:  2 * time.Second,
+		RenewDeadline:  1 * time.Second,
+		RetryPeriod:    200 * time.Millisecond,
+	}
+	defer func() { config.Current.Watcher.LeaderElection = config.LeaderElection{} }()
+	WatcherStore = new(test.DummyStore)
+
+	elected, err := NewResourceWatcher(createFakeClient(), &config.Current.Resources[0], 30*time.Second)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+
+	leases := kubernetesfake.NewClientset().CoordinationV1()
+	elector, err := newLeaderElector(leases, "quasar-test", []*ResourceWatcher{elected})
+	assertions.NoError(err, "unexpected error when creating leader elector")
+	leaderGaugeValue := func() float64 {
+		return testutil.ToFloat64(metrics.GetOrCreateCustom(leaderGauge))
+	}
+	assertions.Zero(leaderGaugeValue())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(done)
+	}()
+
+	assertions.Eventually(elected.leading.Load, 5*time.Second, 100*time.Millisecond, "watcher should lead once elected")
+	assertions.Equal(1.0, leaderGaugeValue())
+
+	lease, err := leases.Leases("playground").Get(ctx, "quasar-watcher", v1.GetOptions{})
+	if assertions.NoError(err) {
+		assertions.Equal("quasar-test", *lease.Spec.HolderIdentity)
+	}
+
+	elected.Stop()
+	cancel()
+	<-done
+	assertions.Zero(leaderGaugeValue(), "leadership should end with the election")
+}
+
+func TestLeaderElection_InvalidConfig(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	config.Current.Watcher.LeaderElection = config.LeaderElection{
+		Enabled:        true,
+		LeaseName:      "quasar-watcher",
+		LeaseNamespace: "playground",
+		LeaseDuration:  1 * time.Second,
+		RenewDeadline:  2 * time.Second,
+		RetryPeriod:    200 * time.Millisecond,
+	}
+	defer func() { config.Current.Watcher.LeaderElection = config.LeaderElection{} }()
+	WatcherStore = new(test.DummyStore)
+
+	watcher, err := NewResourceWatcher(createFakeClient(), &config.Current.Resources[0], 30*time.Second)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+
+	leases := kubernetesfake.NewClientset().CoordinationV1()
+	_, err = newLeaderElector(leases, "quasar-test", []*ResourceWatcher{watcher})
+	assertions.Error(err, "lease duration should be greater than the renew deadline")
+}
+
+func TestResourceWatcher_TakeOver(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	config.Current.Watcher.LeaderElection.Enabled = true
+	defer func() { config.Current.Watcher.LeaderElection.Enabled = false }()
+	dummyStore := new(test.DummyStore)
+	WatcherStore = dummyStore
+
+	client := createFakeClient()
+	follower, err := NewResourceWatcher(client, &config.Current.Resources[0], 30*time.Second)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go follower.Start()
+	defer follower.Stop()
+	assertions.Eventually(follower.hasSynced, 5*time.Second, 100*time.Millisecond)
+
+	follower.lead()
+	gvr := config.Current.Resources[0].GetGroupVersionResource()
+	resource := client.Resource(gvr).Namespace("playground")
+	updated := subscriptions[0].DeepCopy()
+	updated.SetResourceVersion("100")
+	_, err = resource.Update(context.Background(), updated, v1.UpdateOptions{})
+	assertions.NoError(err)
+	time.Sleep(1 * time.Second)
+
+	assertions.Zero(dummyStore.AddCalls, "resources known before taking over should not be created again")
+	assertions.Equal(1, dummyStore.UpdateCalls, "changes after taking over should be written as updates")
+
+	for _, informer := range follower.informers {
+		for _, obj := range informer.informer.GetStore().List() {
+			_, found, _ := unstructured.NestedString(obj.(*unstructured.Unstructured).Object, "spec", "environment")
+			assertions.False(found, "the informer cache should not be modified")
+		}
+	}
+
+	follower.follow()
+	follower.mu.Lock()
+	assertions.Empty(follower.written, "written resources should be forgotten when following")
+	follower.mu.Unlock()
+}
+
+// reconcilingStore is a dummy store that records the contexts its periodic reconciliations are bound to.
+type reconcilingStore struct {
+	test.DummyStore
+	mu              sync.Mutex
+	reconciliations []context.Context
+}
+
+func (s *reconcilingStore) InitializeStores(dataSource reconciliation.DataSource, resourceConfig *config.Resource) {
+	s.DummyStore.InitializeResource(dataSource, resourceConfig)
+}
+
+func (s *reconcilingStore) StartReconciliation(ctx context.Context, _ reconciliation.DataSource, _ *config.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconciliations = append(s.reconciliations, ctx)
+}
+
+func (s *reconcilingStore) Reconcile(context.Context, reconciliation.DataSource, *config.Resource) ([]*reconciliation.Result, error) {
+	return nil, nil
+}
+
+func (s *reconcilingStore) DiffReconciliation(context.Context, reconciliation.DataSource, *config.Resource) ([]*reconciliation.Report, error) {
+	return nil, nil
+}
+
+func (s *reconcilingStore) reconciliationContexts() []context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.reconciliations)
+}
+
+func TestLeaderElection_StepDown(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	config.Current.Watcher.LeaderElection = config.LeaderElection{
+		Enabled:        true,
+		LeaseName:      "quasar-watcher",
+		LeaseNamespace: "playground",
+		LeaseDuration:  2 * time.Second,
+		RenewDeadline:  1 * time.Second,
+		RetryPeriod:    200 * time.Millisecond,
+	}
+	defer func() { config.Current.Watcher.LeaderElection = config.LeaderElection{} }()
+	reconcilingStore := new(reconcilingStore)
+	WatcherStore = reconcilingStore
+
+	elected, err := NewResourceWatcher(createFakeClient(), &config.Current.Resources[0], 30*time.Second)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go elected.Start()
+	defer elected.Stop()
+
+	// renewals of the lease fail while the lease is unavailable, so that leadership is lost
+	var unavailable atomic.Bool
+	clientset := kubernetesfake.NewClientset()
+	clientset.PrependReactor("update", "leases", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if unavailable.Load() {
+			return true, nil, errors.New("lease unavailable")
+		}
+		return false, nil, nil
+	})
+
+	elector, err := newLeaderElector(clientset.CoordinationV1(), "quasar-test", []*ResourceWatcher{elected})
+	assertions.NoError(err, "unexpected error when creating leader elector")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runLeaderElection(ctx, elector)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	assertions.Eventually(elected.leading.Load, 5*time.Second, 100*time.Millisecond, "watcher should lead once elected")
+	reconciliations := reconcilingStore.reconciliationContexts()
+	if assertions.Len(reconciliations, 1, "leaders should reconcile the resource") {
+		assertions.NoError(reconciliations[0].Err())
+	}
+
+	unavailable.Store(true)
+	assertions.Eventually(func() bool {
+		return !elected.leading.Load()
+	}, 5*time.Second, 100*time.Millisecond, "watcher should step down once leadership is lost")
+	assertions.Error(reconciliations[0].Err(), "reconciliation should stop with leadership")
+	assertions.Eventually(elected.hasSynced, 5*time.Second, 100*time.Millisecond, "informers should keep running")
+
+	unavailable.Store(false)
+	assertions.Eventually(elected.leading.Load, 5*time.Second, 100*time.Millisecond, "watcher should lead once elected again")
+	reconciliations = reconcilingStore.reconciliationContexts()
+	if assertions.Len(reconciliations, 2, "leaders should reconcile the resource again") {
+		assertions.NoError(reconciliations[1].Err())
+	}
+	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel, zerolog.PanicLevel))
+}
+
+func TestResourceWatcher_DeleteTombstone(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	dummyStore := new(test.DummyStore)
+	WatcherStore = dummyStore
+
+	// watch events are only sent by the test, so that deletions can be missed
+	client := createFakeClient()
+	watches := make(chan *watch.FakeWatcher, 2)
+	client.PrependWatchReactor("*", func(k8stesting.Action) (bool, watch.Interface, error) {
+		fakeWatch := watch.NewFake()
+		watches <- fakeWatch
+		return true, fakeWatch, nil
+	})
+
+	tombstoneWatcher, err := NewResourceWatcher(client, &config.Current.Resources[0], 0)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go tombstoneWatcher.Start()
+	defer tombstoneWatcher.Stop()
+
+	fakeWatch := <-watches
+	assertions.Eventually(tombstoneWatcher.hasSynced, 5*time.Second, 100*time.Millisecond)
+	assertions.Equal(2, dummyStore.AddCalls, "unexpected amount of add calls in the store")
+
+	gvr := config.Current.Resources[0].GetGroupVersionResource()
+	err = client.Resource(gvr).Namespace("playground").Delete(context.Background(), subscriptions[0].GetName(), v1.DeleteOptions{})
+	assertions.NoError(err)
+
+	// an expired watch makes the informer relist and notice the missed deletion
+	fakeWatch.Error(&v1.Status{Status: v1.StatusFailure, Code: 410, Reason: v1.StatusReasonExpired})
+	<-watches
+	time.Sleep(1 * time.Second)
+
+	assertions.Equal(1, dummyStore.DeleteCalls, "missed deletions should be deleted from the store")
+	assertions.Equal(
+		0,
+		test.LogRecorder.GetRecordCount(zerolog.WarnLevel, zerolog.ErrorLevel, zerolog.PanicLevel),
+		"found unexpected warnings, errors and/or panics in the logs",
+	)
+}
+
+// flakyStore is a dummy store whose first creates fail.
+type flakyStore struct {
+	test.DummyStore
+	mu       sync.Mutex
+	failures int
+}
+
+func (s *flakyStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("store unavailable")
+	}
+	return s.DummyStore.Create(ctx, obj)
+}
+
+func (s *flakyStore) addCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.AddCalls
+}
+
+func TestResourceWatcher_RetriesFailedWrites(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	config.Current.Watcher.Queue = config.WatcherQueue{Workers: 2, MaxRetries: 3}
+	defer func() { config.Current.Watcher.Queue = config.WatcherQueue{} }()
+	flaky := &flakyStore{failures: 3}
+	WatcherStore = flaky
+
+	retryingWatcher, err := NewResourceWatcher(createFakeClient(), &config.Current.Resources[0], 0)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go retryingWatcher.Start()
+	defer retryingWatcher.Stop()
+
+	assertions.Eventually(func() bool {
+		return flaky.addCalls() == 2
+	}, 5*time.Second, 100*time.Millisecond, "failed writes should be retried")
+
+	retries := metrics.WorkqueueMetricsProvider.NewRetriesMetric(config.Current.Resources[0].GetGroupVersionName())
+	assertions.GreaterOrEqual(testutil.ToFloat64(retries.(prometheus.Counter)), 3.0)
+	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "retries should not have been exhausted")
+}
+
+func TestResourceWatcher_Selectors(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	dummyStore := new(test.DummyStore)
+	WatcherStore = dummyStore
+
+	selected := subscriptions[0].DeepCopy()
+	selected.SetLabels(map[string]string{"environment": "playground"})
+	other := subscriptions[1].DeepCopy()
+	other.SetLabels(map[string]string{"environment": "integration"})
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), selected, other)
+
+	resourceConfig := config.Current.Resources[0]
+	resourceConfig.Kubernetes.LabelSelector = "environment=playground"
+	selectingWatcher, err := NewResourceWatcher(client, &resourceConfig, 0)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go selectingWatcher.Start()
+	defer selectingWatcher.Stop()
+
+	assertions.Eventually(selectingWatcher.hasSynced, 5*time.Second, 100*time.Millisecond)
+	time.Sleep(1 * time.Second)
+	assertions.Equal(1, dummyStore.AddCalls, "only resources matching the selector should be written")
+}
+
+func newNamespace(name string, labels map[string]string) *unstructured.Unstructured {
+	namespace := new(unstructured.Unstructured)
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName(name)
+	namespace.SetLabels(labels)
+	return namespace
+}
+
+func subscriptionIn(subscription *unstructured.Unstructured, namespace string) *unstructured.Unstructured {
+	namespaced := subscription.DeepCopy()
+	namespaced.SetNamespace(namespace)
+	return namespaced
+}
+
+func (w *ResourceWatcher) watchedNamespaces() []string {
+	w.informersMu.Lock()
+	defer w.informersMu.Unlock()
+
+	namespaces := make([]string, 0, len(w.informers))
+	for namespace := range w.informers {
+		namespaces = append(namespaces, namespace)
+	}
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+func TestResourceWatcher_Namespaces(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	dummyStore := new(test.DummyStore)
+	WatcherStore = dummyStore
+
+	selected := map[string]string{"quasar": "enabled"}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newNamespace("playground", selected),
+		newNamespace("integration", nil),
+		subscriptionIn(subscriptions[0], "playground"),
+		subscriptionIn(subscriptions[1], "integration"),
+		subscriptionIn(subscriptions[1], "static"),
+	)
+
+	resourceConfig := config.Current.Resources[0]
+	resourceConfig.Kubernetes.Namespace = ""
+	resourceConfig.Kubernetes.Namespaces = []string{"static"}
+	resourceConfig.Kubernetes.NamespaceSelector = "quasar=enabled"
+	namespacedWatcher, err := NewResourceWatcher(client, &resourceConfig, 0)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go namespacedWatcher.Start()
+	defer namespacedWatcher.Stop()
+
+	assertions.Eventually(func() bool {
+		return dummyStore.AddCalls == 2
+	}, 5*time.Second, 100*time.Millisecond, "resources of configured and selected namespaces should be written")
+	assertions.Equal([]string{"playground", "static"}, namespacedWatcher.watchedNamespaces())
+
+	ctx := context.Background()
+	gvr := resourceConfig.GetGroupVersionResource()
+	_, err = client.Resource(reconciliation.NamespaceResource).Create(ctx, newNamespace("tenant", selected), v1.CreateOptions{})
+	assertions.NoError(err)
+	assertions.Eventually(func() bool {
+		return slices.Contains(namespacedWatcher.watchedNamespaces(), "tenant")
+	}, 5*time.Second, 100*time.Millisecond, "namespaces starting to match the selector should be watched")
+
+	_, err = client.Resource(gvr).Namespace("tenant").Create(ctx, subscriptionIn(subscriptions[0], "tenant"), v1.CreateOptions{})
+	assertions.NoError(err)
+	assertions.Eventually(func() bool {
+		return dummyStore.AddCalls == 3
+	}, 5*time.Second, 100*time.Millisecond, "resources of newly selected namespaces should be written")
+
+	err = client.Resource(reconciliation.NamespaceResource).Delete(ctx, "playground", v1.DeleteOptions{})
+	assertions.NoError(err)
+	assertions.Eventually(func() bool {
+		return !slices.Contains(namespacedWatcher.watchedNamespaces(), "playground")
+	}, 5*time.Second, 100*time.Millisecond, "namespaces that stopped matching the selector should not be watched")
+	assertions.Equal([]string{"static", "tenant"}, namespacedWatcher.watchedNamespaces())
+	assertions.Equal([]string{"static/", "tenant/"}, namespacedWatcher.writtenNamespaces(),
+		"resources of namespaces that are not watched anymore should be forgotten")
+}
+
+func (w *ResourceWatcher) writtenNamespaces() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	namespaces := make([]string, 0)
+	for key := range w.written {
+		namespace, _, _ := strings.Cut(key, "/")
+		if !slices.Contains(namespaces, namespace+"/") {
+			namespaces = append(namespaces, namespace+"/")
+		}
+	}
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+func TestResourceWatcher_ForbiddenNamespace(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	dummyStore := new(test.DummyStore)
+	WatcherStore = dummyStore
+
+	selected := map[string]string{"quasar": "enabled"}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newNamespace("playground", selected),
+		newNamespace("forbidden", selected),
+		subscriptionIn(subscriptions[0], "playground"),
+		subscriptionIn(subscriptions[1], "forbidden"),
+	)
+	client.PrependReactor("list", "subscriptions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "forbidden" {
+			return true, nil, errors.New("subscriptions are forbidden")
+		}
+		return false, nil, nil
+	})
+
+	resourceConfig := config.Current.Resources[0]
+	resourceConfig.Kubernetes.Namespace = ""
+	resourceConfig.Kubernetes.NamespaceSelector = "quasar=enabled"
+	namespacedWatcher, err := NewResourceWatcher(client, &resourceConfig, 0)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+	go namespacedWatcher.Start()
+	defer namespacedWatcher.Stop()
+
+	assertions.Eventually(func() bool {
+		return slices.Equal([]string{"playground"}, namespacedWatcher.watchedNamespaces())
+	}, 5*time.Second, 100*time.Millisecond, "namespaces that can not be watched should be unwatched")
+	assertions.Eventually(namespacedWatcher.hasSynced, 5*time.Second, 100*time.Millisecond)
+	assertions.Eventually(func() bool {
+		return dummyStore.AddCalls == 1
+	}, 5*time.Second, 100*time.Millisecond, "resources of the other namespaces should be written")
+}
+
+func TestResourceWatcher_HandleWatchErrorAsFollower(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	WatcherStore = new(test.DummyStore)
+
+	resourceConfig := config.Current.Resources[0]
+	resourceConfig.Kubernetes.Namespace = ""
+	resourceConfig.Kubernetes.Namespaces = []string{"playground", "integration"}
+	follower, err := NewResourceWatcher(createFakeClient(), &resourceConfig, 0)
+	assertions.NoError(err, "unexpected error when creating new resource watcher")
+
+	for _, informer := range follower.informers {
+		follower.handleWatchError(informer, errors.New("watch failed"))
+		follower.handleWatchError(informer, errors.New("watch failed"))
+		assertions.False(informer.failed.Load(), "followers should not consume the replay of an informer")
+	}
+	assertions.False(follower.replayed.Load(), "followers should not replay the resource")
+	assertions.Equal(4, test.LogRecorder.GetRecordCount(zerolog.WarnLevel))
+}
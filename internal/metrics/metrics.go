@@ -27,6 +27,7 @@ func init() {
 	registry = prometheus.NewRegistry()
 	gauges = make(map[string]*prometheus.GaugeVec)
 	counters = make(map[string]*prometheus.CounterVec)
+	WorkqueueMetricsProvider = newWorkqueueMetricsProvider()
 }
 
 func GetOrCreate(resourceConfig *config.Resource) *prometheus.GaugeVec {
@@ -51,7 +52,9 @@ func GetOrCreate(resourceConfig *config.Resource) *prometheus.GaugeVec {
 	return gauge
 }
 
-func GetOrCreateCustom(name string) *prometheus.GaugeVec {
+// GetOrCreateCustom returns the gauge with the given name, which is created with the given label names if it does not
+// exist yet.
+func GetOrCreateCustom(name string, labels ...string) *prometheus.GaugeVec {
 	gaugeName := strings.ReplaceAll(name, ".", "_")
 
 	gauge, ok := gauges[gaugeName]
@@ -59,7 +62,7 @@ func GetOrCreateCustom(name string) *prometheus.GaugeVec {
 		gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      gaugeName,
-		}, []string{})
+		}, labels)
 
 		gauges[gaugeName] = gauge
 		if err := registry.Register(gauge); err != nil {
@@ -74,7 +77,9 @@ func GetOrCreateCustom(name string) *prometheus.GaugeVec {
 	return gauge
 }
 
-func GetOrCreateCustomCounter(name string) *prometheus.CounterVec {
+// GetOrCreateCustomCounter returns the counter with the given name, which is created with the given label names if it
+// does not exist yet.
+func GetOrCreateCustomCounter(name string, labels ...string) *prometheus.CounterVec {
 	key := strings.ReplaceAll(name, ".", "_")
 	if c, ok := counters[key]; ok {
 		return c
@@ -83,7 +88,7 @@ func GetOrCreateCustomCounter(name string) *prometheus.CounterVec {
 		Namespace: namespace,
 		Name:      key,
 		Help:      "Custom counter " + key,
-	}, []string{})
+	}, labels)
 	if err := registry.Register(counter); err != nil {
 		log.Error().Err(err).
 			Str("metric", namespace+"_"+key).
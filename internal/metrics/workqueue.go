@@ -0,0 +1,113 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// WorkqueueMetricsProvider exposes the metrics of work queues labeled with the name of the queue.
+var WorkqueueMetricsProvider workqueue.MetricsProvider
+
+type workqueueMetricsProvider struct {
+	depth          *prometheus.GaugeVec
+	adds           *prometheus.CounterVec
+	latency        *prometheus.HistogramVec
+	workDuration   *prometheus.HistogramVec
+	unfinished     *prometheus.GaugeVec
+	longestRunning *prometheus.GaugeVec
+	retries        *prometheus.CounterVec
+}
+
+func newWorkqueueMetricsProvider() *workqueueMetricsProvider {
+	labels := []string{"name"}
+	buckets := prometheus.ExponentialBuckets(0.001, 4, 10)
+	provider := &workqueueMetricsProvider{
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "depth",
+			Help:      "Number of events waiting in the work queue",
+		}, labels),
+		adds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "adds_total",
+			Help:      "Number of events added to the work queue",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "queue_duration_seconds",
+			Help:      "Time events wait in the work queue before being processed",
+			Buckets:   buckets,
+		}, labels),
+		workDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "work_duration_seconds",
+			Help:      "Time processing an event from the work queue takes",
+			Buckets:   buckets,
+		}, labels),
+		unfinished: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "unfinished_work_seconds",
+			Help:      "Time the events currently being processed have been in progress",
+		}, labels),
+		longestRunning: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "longest_running_processor_seconds",
+			Help:      "Time the longest running event has been in progress",
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "workqueue",
+			Name:      "retries_total",
+			Help:      "Number of retries of failed events",
+		}, labels),
+	}
+
+	registry.MustRegister(
+		provider.depth,
+		provider.adds,
+		provider.latency,
+		provider.workDuration,
+		provider.unfinished,
+		provider.longestRunning,
+		provider.retries,
+	)
+	return provider
+}
+
+func (p *workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.adds.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.latency.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.workDuration.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.unfinished.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.longestRunning.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.retries.WithLabelValues(name)
+}
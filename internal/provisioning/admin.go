@@ -0,0 +1,134 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provisioning
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/store"
+)
+
+// diffReconciliation handles GET requests to report the differences a reconciliation of a resource would resolve
+// without writing anything
+// URL params: group, version, resource
+// Response: HTTP 200 with a report for each reconciled store
+func diffReconciliation(ctx *fiber.Ctx) error {
+	gvr, err := getGvrFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug().Fields(generateLogAttributes("Diff-Reconciliation", "", gvr)).Msg("Request received for resource")
+
+	reconciler, err := getReconciler()
+	if err != nil {
+		return err
+	}
+
+	resourceConfig := getResourceConfigForGvr(gvr)
+	dataSource := reconciliation.NewDataSourceFromStore(provisioningApiStore, *resourceConfig)
+	reports, err := reconciler.DiffReconciliation(ctx.UserContext(), dataSource, resourceConfig)
+	if err != nil {
+		logger.Error().Err(err).Fields(generateLogAttributes("Diff-Reconciliation", "", gvr)).Msg("Failed to diff resources")
+		return newStoreError(ctx, err, "Failed to diff resources")
+	}
+
+	logger.Debug().Fields(generateLogAttributes("Diff-Reconciliation", "", gvr)).Msg("Request successfully")
+	return ctx.Status(fiber.StatusOK).JSON(ReconciliationResponse{
+		Reports: reports,
+	})
+}
+
+// reconcileResource handles POST requests to reconcile the stores of a resource right away
+// URL params: group, version, resource
+// Response: HTTP 200 with the result for each reconciled store
+func reconcileResource(ctx *fiber.Ctx) error {
+	gvr, err := getGvrFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug().Fields(generateLogAttributes("Reconcile", "", gvr)).Msg("Request received for resource")
+
+	reconciler, err := getReconciler()
+	if err != nil {
+		return err
+	}
+
+	resourceConfig := getResourceConfigForGvr(gvr)
+	dataSource := reconciliation.NewDataSourceFromStore(provisioningApiStore, *resourceConfig)
+	results, err := reconciler.Reconcile(ctx.UserContext(), dataSource, resourceConfig)
+	if err != nil {
+		logger.Error().Err(err).Fields(generateLogAttributes("Reconcile", "", gvr)).Msg("Failed to reconcile resources")
+		return newReconciliationError(ctx, err, "Failed to reconcile resources")
+	}
+
+	logger.Debug().Fields(generateLogAttributes("Reconcile", "", gvr)).Msg("Request successfully")
+	return ctx.Status(fiber.StatusOK).JSON(ReconciliationResponse{
+		Results: results,
+	})
+}
+
+// reconcileAllResources handles POST requests to reconcile the stores of all resources right away.
+// Resources with disabled reconciliation are skipped.
+// Response: HTTP 200 with the result for each reconciled resource and store
+func reconcileAllResources(ctx *fiber.Ctx) error {
+	logger.Debug().Str("operation", "Reconcile-All").Msg("Request received for all resources")
+
+	reconciler, err := getReconciler()
+	if err != nil {
+		return err
+	}
+
+	results := make([]*reconciliation.Result, 0)
+	var errs []error
+	for i := range config.Current.Resources {
+		resourceConfig := &config.Current.Resources[i]
+		if !resourceConfig.Reconciliation.IsEnabled() {
+			continue
+		}
+
+		dataSource := reconciliation.NewDataSourceFromStore(provisioningApiStore, *resourceConfig)
+		resourceResults, err := reconciler.Reconcile(ctx.UserContext(), dataSource, resourceConfig)
+		results = append(results, resourceResults...)
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		logger.Error().Err(err).Msg("Failed to reconcile resources")
+		return newReconciliationError(ctx, err, "Failed to reconcile resources")
+	}
+
+	logger.Debug().Msg("Request successfully")
+	return ctx.Status(fiber.StatusOK).JSON(ReconciliationResponse{
+		Results: results,
+	})
+}
+
+// getReconciler returns the provisioning store as reconciler or an error if it does not support reconciliation.
+func getReconciler() (store.Reconciler, error) {
+	reconciler, ok := provisioningApiStore.(store.Reconciler)
+	if !ok {
+		return nil, &fiber.Error{
+			Code:    fiber.StatusNotImplemented,
+			Message: "Store does not support reconciliation",
+		}
+	}
+	return reconciler, nil
+}
+
+// newReconciliationError returns a 409 if the reconciliation is disabled or already in progress and a store error otherwise.
+func newReconciliationError(ctx *fiber.Ctx, err error, message string) *fiber.Error {
+	if errors.Is(err, reconciliation.ErrReconciliationInProgress) || errors.Is(err, reconciliation.ErrReconciliationDisabled) {
+		return &fiber.Error{
+			Code:    fiber.StatusConflict,
+			Message: message + ": reconciliation is disabled or already in progress",
+		}
+	}
+	return newStoreError(ctx, err, message)
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/test"
+)
+
+// reconcilingMockDualStore is a mock store that reports a fixed difference to the data source and adds it when reconciled
+type reconcilingMockDualStore struct {
+	*MockDualStoreWithErrors
+	dataSource   reconciliation.DataSource
+	reconcileErr error
+}
+
+func (m *reconcilingMockDualStore) DiffReconciliation(
+	_ context.Context,
+	dataSource reconciliation.DataSource,
+	resourceConfig *config.Resource,
+) ([]*reconciliation.Report, error) {
+	m.dataSource = dataSource
+	return []*reconciliation.Report{{
+		Dataset:  resourceConfig.GetGroupVersionName(),
+		Store:    "hazelcast",
+		Missing:  []string{"test-subscription"},
+		Stale:    []string{},
+		Orphaned: []string{},
+		Samples:  []reconciliation.Sample{},
+	}}, nil
+}
+
+func (m *reconcilingMockDualStore) Reconcile(
+	_ context.Context,
+	_ reconciliation.DataSource,
+	resourceConfig *config.Resource,
+) ([]*reconciliation.Result, error) {
+	if m.reconcileErr != nil {
+		return nil, m.reconcileErr
+	}
+	return []*reconciliation.Result{{Dataset: resourceConfig.GetGroupVersionName(), Store: "hazelcast", Added: 1}}, nil
+}
+
+func setupAdminTestApp() *fiber.App {
+	app := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+	})
+
+	app.Post("/api/v1/admin/reconcile", reconcileAllResources)
+	admin := app.Group("/api/v1/admin/reconcile/:group/:version/:resource", withGvr)
+	admin.Post("/", reconcileResource)
+	admin.Get("/diff", diffReconciliation)
+
+	if logger == nil {
+		logger = createTestLogger()
+	}
+	return app
+}
+
+// TestDiffReconciliation verifies diffReconciliation returns the reports of the store manager
+func TestDiffReconciliation(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	app := setupAdminTestApp()
+	mockStore := &reconcilingMockDualStore{MockDualStoreWithErrors: NewMockDualStoreWithErrors()}
+	provisioningApiStore = mockStore
+	defer func() { provisioningApiStore = nil }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/reconcile/subscriber.horizon.telekom.de/v1/subscriptions/diff", nil)
+	resp, err := app.Test(req)
+	assertions.NoError(err)
+	assertions.Equal(200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var response ReconciliationResponse
+	assertions.NoError(json.Unmarshal(body, &response))
+	if assertions.Len(response.Reports, 1) {
+		assertions.Equal("hazelcast", response.Reports[0].Store)
+		assertions.Equal([]string{"test-subscription"}, response.Reports[0].Missing)
+	}
+
+	storeSource, ok := mockStore.dataSource.(*reconciliation.StoreDataSource)
+	if assertions.True(ok, "resources should be diffed against the provisioning store") {
+		assertions.Equal(provisioningApiStore, storeSource.Store())
+	}
+
+	provisioningApiStore = NewMockDualStoreWithErrors()
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/admin/reconcile/subscriber.horizon.telekom.de/v1/subscriptions/diff", nil))
+	assertions.NoError(err)
+	assertions.Equal(fiber.StatusNotImplemented, resp.StatusCode, "stores without reconciliation should be rejected")
+}
+
+// TestReconcileResource verifies reconcileResource returns the results of the store manager
+func TestReconcileResource(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	app := setupAdminTestApp()
+	mockStore := &reconcilingMockDualStore{MockDualStoreWithErrors: NewMockDualStoreWithErrors()}
+	provisioningApiStore = mockStore
+	defer func() { provisioningApiStore = nil }()
+
+	for _, path := range []string{"/api/v1/admin/reconcile/subscriber.horizon.telekom.de/v1/subscriptions", "/api/v1/admin/reconcile"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, path, nil))
+		assertions.NoError(err)
+		assertions.Equal(200, resp.StatusCode, path)
+
+		body, _ := io.ReadAll(resp.Body)
+		var response ReconciliationResponse
+		assertions.NoError(json.Unmarshal(body, &response))
+		if assertions.Len(response.Results, 1, path) {
+			assertions.Equal("subscriptions.subscriber.horizon.telekom.de.v1", response.Results[0].Dataset)
+			assertions.Equal(1, response.Results[0].Added)
+		}
+	}
+
+	mockStore.reconcileErr = fmt.Errorf("store %q: %w", "hazelcast", reconciliation.ErrReconciliationInProgress)
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile/subscriber.horizon.telekom.de/v1/subscriptions", nil))
+	assertions.NoError(err)
+	assertions.Equal(fiber.StatusConflict, resp.StatusCode, "reconciliations in progress should be reported as conflict")
+
+	mockStore.reconcileErr = errors.New("mock reconcile error")
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile", nil))
+	assertions.NoError(err)
+	assertions.Equal(fiber.StatusInternalServerError, resp.StatusCode)
+}
@@ -24,12 +24,9 @@ func putResource(ctx *fiber.Ctx) error {
 
 	logger.Debug().Fields(generateLogAttributes("Put", id, gvr)).Msg("Request received for resource")
 
-	if err := provisioningApiStore.Create(&resource); err != nil {
+	if err := provisioningApiStore.Create(ctx.UserContext(), &resource); err != nil {
 		logger.Error().Err(err).Fields(generateLogAttributes("Put", id, gvr)).Msg("Failed to put resource")
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Failed to put resource",
-		}
+		return newStoreError(ctx, err, "Failed to put resource")
 	}
 	logger.Debug().Fields(generateLogAttributes("Put", id, gvr)).Msg("Request successfully")
 	return ctx.Status(fiber.StatusOK).Send(nil)
@@ -46,7 +43,8 @@ func getResource(ctx *fiber.Ctx) error {
 
 	logger.Debug().Fields(generateLogAttributes("Get", id, gvr)).Msg("Request received for resource")
 
-	resource, err := provisioningApiStore.Read(getDataSetForGvr(gvr), id)
+	readCtx, source := store.WithReadSource(ctx.UserContext())
+	resource, err := provisioningApiStore.Read(readCtx, getDataSetForGvr(gvr), id)
 	if err != nil {
 		if errors.Is(err, store.ErrResourceNotFound) {
 			return &fiber.Error{
@@ -55,13 +53,11 @@ func getResource(ctx *fiber.Ctx) error {
 			}
 		}
 		logger.Error().Err(err).Fields(generateLogAttributes("Get", id, gvr)).Msg("Failed to get resource")
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Failed to get resource",
-		}
+		return newStoreError(ctx, err, "Failed to get resource")
 	}
 
 	logger.Debug().Fields(generateLogAttributes("Get", id, gvr)).Msg("Request successfully")
+	setDegradedSourceHeader(ctx, source)
 	return ctx.Status(fiber.StatusOK).JSON(resource)
 }
 
@@ -88,16 +84,15 @@ func listResources(ctx *fiber.Ctx) error {
 		}
 	}
 
-	resources, err := provisioningApiStore.List(getDataSetForGvr(gvr), fieldSelector, limit)
+	readCtx, source := store.WithReadSource(ctx.UserContext())
+	resources, err := provisioningApiStore.List(readCtx, getDataSetForGvr(gvr), fieldSelector, limit)
 	if err != nil {
 		logger.Error().Err(err).Fields(generateLogAttributes("List-Resources", "", gvr)).Msg("Failed to list resources")
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Failed to list resources",
-		}
+		return newStoreError(ctx, err, "Failed to list resources")
 	}
 
 	logger.Debug().Fields(generateLogAttributes("List-Resources", "", gvr)).Msg("Request successfully")
+	setDegradedSourceHeader(ctx, source)
 	return ctx.Status(fiber.StatusOK).JSON(ResourceResponse{
 		Items: resources,
 		Count: len(resources),
@@ -115,16 +110,15 @@ func listKeys(ctx *fiber.Ctx) error {
 
 	logger.Debug().Fields(generateLogAttributes("List-Keys", "", gvr)).Msg("Request received for resource")
 
-	keys, err := provisioningApiStore.Keys(getDataSetForGvr(gvr))
+	readCtx, source := store.WithReadSource(ctx.UserContext())
+	keys, err := provisioningApiStore.Keys(readCtx, getDataSetForGvr(gvr))
 	if err != nil {
 		logger.Error().Err(err).Fields(generateLogAttributes("List-Keys", "", gvr)).Msg("Failed to list keys")
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Failed to list keys",
-		}
+		return newStoreError(ctx, err, "Failed to list keys")
 	}
 
 	logger.Debug().Fields(generateLogAttributes("List-Keys", "", gvr)).Msg("Request successfully")
+	setDegradedSourceHeader(ctx, source)
 	return ctx.Status(fiber.StatusOK).JSON(ResourceResponse{
 		Keys: keys,
 	})
@@ -141,16 +135,15 @@ func countResources(ctx *fiber.Ctx) error {
 
 	logger.Debug().Fields(generateLogAttributes("Count-Resources", "", gvr)).Msg("Request received for resource")
 
-	count, err := provisioningApiStore.Count(getDataSetForGvr(gvr))
+	readCtx, source := store.WithReadSource(ctx.UserContext())
+	count, err := provisioningApiStore.Count(readCtx, getDataSetForGvr(gvr))
 	if err != nil {
 		logger.Error().Err(err).Fields(generateLogAttributes("Count-Resources", "", gvr)).Msg("Failed to count resources")
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Failed to count resources",
-		}
+		return newStoreError(ctx, err, "Failed to count resources")
 	}
 
 	logger.Debug().Fields(generateLogAttributes("Count-Resources", "", gvr)).Msg("Request successfully")
+	setDegradedSourceHeader(ctx, source)
 	return ctx.Status(fiber.StatusOK).JSON(ResourceResponse{
 		Count: count,
 	})
@@ -168,12 +161,9 @@ func deleteResource(ctx *fiber.Ctx) error {
 
 	logger.Debug().Fields(generateLogAttributes("Delete", id, gvr)).Msg("Request received for resource")
 
-	if err := provisioningApiStore.Delete(&resource); err != nil {
+	if err := provisioningApiStore.Delete(ctx.UserContext(), &resource); err != nil {
 		logger.Error().Err(err).Fields(generateLogAttributes("Delete", id, gvr)).Msg("Failed to delete resource")
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Failed to delete resource",
-		}
+		return newStoreError(ctx, err, "Failed to delete resource")
 	}
 
 	logger.Debug().Fields(generateLogAttributes("Delete", id, gvr)).Msg("Request successfully")
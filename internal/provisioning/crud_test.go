@@ -7,6 +7,7 @@
 package provisioning
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -54,7 +56,7 @@ func (m *MockDualStoreWithErrors) Initialize() {}
 func (m *MockDualStoreWithErrors) InitializeResource(reconciliation.DataSource, *config.Resource) {
 }
 
-func (m *MockDualStoreWithErrors) Create(obj *unstructured.Unstructured) error {
+func (m *MockDualStoreWithErrors) Create(_ context.Context, obj *unstructured.Unstructured) error {
 	if m.CreateError {
 		return errors.New("mock create error")
 	}
@@ -62,12 +64,12 @@ func (m *MockDualStoreWithErrors) Create(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (m *MockDualStoreWithErrors) Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+func (m *MockDualStoreWithErrors) Update(_ context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
 	_, _ = oldObj, newObj
 	return nil
 }
 
-func (m *MockDualStoreWithErrors) Delete(obj *unstructured.Unstructured) error {
+func (m *MockDualStoreWithErrors) Delete(_ context.Context, obj *unstructured.Unstructured) error {
 	if m.DeleteError {
 		return errors.New("mock delete error")
 	}
@@ -75,7 +77,7 @@ func (m *MockDualStoreWithErrors) Delete(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (m *MockDualStoreWithErrors) Count(dataset string) (int, error) {
+func (m *MockDualStoreWithErrors) Count(_ context.Context, dataset string) (int, error) {
 	_ = dataset
 	if m.CountError {
 		return 0, errors.New("mock count error")
@@ -83,7 +85,7 @@ func (m *MockDualStoreWithErrors) Count(dataset string) (int, error) {
 	return len(m.resources), nil
 }
 
-func (m *MockDualStoreWithErrors) Keys(dataset string) ([]string, error) {
+func (m *MockDualStoreWithErrors) Keys(_ context.Context, dataset string) ([]string, error) {
 	_ = dataset
 	if m.KeysError {
 		return nil, errors.New("mock keys error")
@@ -95,7 +97,7 @@ func (m *MockDualStoreWithErrors) Keys(dataset string) ([]string, error) {
 	return keys, nil
 }
 
-func (m *MockDualStoreWithErrors) Read(dataset string, key string) (*unstructured.Unstructured, error) {
+func (m *MockDualStoreWithErrors) Read(_ context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
 	_ = dataset
 	if m.ReadError {
 		return nil, errors.New("mock read error")
@@ -109,7 +111,7 @@ func (m *MockDualStoreWithErrors) Read(dataset string, key string) (*unstructure
 	return nil, store.ErrResourceNotFound
 }
 
-func (m *MockDualStoreWithErrors) List(dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+func (m *MockDualStoreWithErrors) List(_ context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
 	_, _ = dataset, fieldSelector
 	if m.ListError {
 		return nil, errors.New("mock list error")
@@ -226,6 +228,7 @@ func TestGetResource_Success(t *testing.T) {
 	assertions.NoError(err)
 	assertions.NotNil(response)
 	assertions.Equal("test-subscription", response.GetName())
+	assertions.Empty(resp.Header.Get(degradedSourceHeader), "reads from the primary store should not be marked as degraded")
 }
 
 // TestGetResource_NotFound verifies getResource returns 404 when resource does not exist
@@ -266,6 +269,38 @@ func TestGetResource_StoreError(t *testing.T) {
 	assertions.Equal(500, resp.StatusCode)
 }
 
+// degradedMockDualStore is a mock store whose reads are served by a replica
+type degradedMockDualStore struct {
+	*MockDualStoreWithErrors
+}
+
+func (m *degradedMockDualStore) Read(ctx context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	if source := store.ReadSourceFrom(ctx); source != nil {
+		source.Store, source.Degraded = "mongo", true
+	}
+	return m.MockDualStoreWithErrors.Read(ctx, dataset, key)
+}
+
+// TestGetResource_DegradedSource verifies getResource indicates reads served by a replica
+func TestGetResource_DegradedSource(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	app := setupCrudTestApp()
+	mockStore := NewMockDualStoreWithErrors()
+	mockStore.resources["test-subscription"] = createTestResource("test-subscription", "Subscription", "subscriber.horizon.telekom.de/v1")
+
+	provisioningApiStore = &degradedMockDualStore{mockStore}
+	defer func() { provisioningApiStore = nil }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resources/subscriber.horizon.telekom.de/v1/subscriptions/test-subscription", nil)
+	resp, err := app.Test(req)
+
+	assertions.NoError(err)
+	assertions.Equal(200, resp.StatusCode)
+	assertions.Equal("mongo", resp.Header.Get(degradedSourceHeader))
+}
+
 // Tests for listResources()
 // ========================================================================
 
@@ -457,6 +492,39 @@ func TestPutResource_StoreError(t *testing.T) {
 	assertions.Equal(500, resp.StatusCode)
 }
 
+// circuitOpenMockDualStore is a mock store whose circuit breaker is open
+type circuitOpenMockDualStore struct {
+	*MockDualStoreWithErrors
+}
+
+func (m *circuitOpenMockDualStore) Create(context.Context, *unstructured.Unstructured) error {
+	return &store.CircuitOpenError{StoreType: "mongo", RetryAfter: 1500 * time.Millisecond}
+}
+
+// TestPutResource_CircuitOpen verifies putResource returns 503 with Retry-After while the circuit breaker is open
+func TestPutResource_CircuitOpen(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	app := setupCrudTestApp()
+	provisioningApiStore = &circuitOpenMockDualStore{NewMockDualStoreWithErrors()}
+	defer func() { provisioningApiStore = nil }()
+
+	body := createTestResourceBody("test-subscription", "Subscription", "subscriber.horizon.telekom.de/v1")
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/api/v1/resources/subscriber.horizon.telekom.de/v1/subscriptions/test-subscription",
+		strings.NewReader(body),
+	)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assertions.NoError(err)
+	assertions.Equal(503, resp.StatusCode)
+	assertions.Equal("2", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
 // TestPutResource_InvalidJSON verifies putResource returns 400 when JSON body is invalid
 func TestPutResource_InvalidJSON(t *testing.T) {
 	assertions := assert.New(t)
@@ -5,6 +5,7 @@
 package provisioning
 
 import (
+	"context"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -17,7 +18,7 @@ import (
 func scheduleMetricGeneration(store store.Store, resourceConfig *config.Resource) {
 	go func() {
 		for {
-			resources, err := store.List(resourceConfig.GetGroupVersionName(), "", 0)
+			resources, err := store.List(context.Background(), resourceConfig.GetGroupVersionName(), "", 0)
 			if err != nil {
 				log.Error().Str("task", "metrics").Err(err).Msg("Error listing resources for metric generation")
 				time.Sleep(config.Current.Metrics.Timeout)
@@ -5,6 +5,7 @@
 package provisioning
 
 import (
+	"context"
 	"slices"
 
 	"github.com/gofiber/fiber/v2"
@@ -30,6 +31,16 @@ func withTrustedClients(trustedClients []string) fiber.Handler {
 	}
 }
 
+// withRequestContext binds the store operations of a request to its lifetime, so that operations still running
+// after the handler returned (e.g. because the store did not respond in time) are cancelled.
+func withRequestContext(ctx *fiber.Ctx) error {
+	requestCtx, cancel := context.WithCancel(ctx.UserContext())
+	defer cancel()
+
+	ctx.SetUserContext(requestCtx)
+	return ctx.Next()
+}
+
 func withKubernetesResource(ctx *fiber.Ctx) error {
 	resource := new(unstructured.Unstructured)
 	if err := resource.UnmarshalJSON(ctx.Body()); err != nil {
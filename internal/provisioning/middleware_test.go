@@ -7,6 +7,7 @@
 package provisioning
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -101,6 +102,27 @@ func TestWithGvr(t *testing.T) {
 	assertions.Equal(0, errorCount, "no errors should be logged by middleware itself")
 }
 
+func TestWithRequestContext(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	app := createTestFiberApp()
+
+	var requestCtx context.Context
+	app.Get("/", withRequestContext, func(c *fiber.Ctx) error {
+		requestCtx = c.UserContext()
+		assertions.NoError(requestCtx.Err(), "context should not be cancelled while the request is handled")
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	assertions.NoError(err)
+	assertions.Equal(fiber.StatusOK, resp.StatusCode)
+	if assertions.NotNil(requestCtx) {
+		assertions.ErrorIs(requestCtx.Err(), context.Canceled, "context should be cancelled once the request has been handled")
+	}
+}
+
 func TestWithResourceId(t *testing.T) {
 	assertions := assert.New(t)
 	defer test.LogRecorder.Reset()
@@ -52,13 +52,18 @@ func setupService(logger *zerolog.Logger) {
 		log.Warn().Msg("Provisioning service is running without security, this is not recommended for production environments")
 	}
 
-	v1 := service.Group("/api/v1/resources/:group/:version/:resource", withGvr)
+	v1 := service.Group("/api/v1/resources/:group/:version/:resource", withRequestContext, withGvr)
 	v1.Get("/", listResources)
 	v1.Get("/keys", listKeys)
 	v1.Get("/count", countResources)
 	v1.Get("/:id", withResourceId, getResource)
 	v1.Put("/:id", withResourceId, withKubernetesResource, putResource)
 	v1.Delete("/:id", withResourceId, withKubernetesResource, deleteResource)
+
+	service.Post("/api/v1/admin/reconcile", withRequestContext, reconcileAllResources)
+	admin := service.Group("/api/v1/admin/reconcile/:group/:version/:resource", withRequestContext, withGvr)
+	admin.Post("/", reconcileResource)
+	admin.Get("/diff", diffReconciliation)
 }
 
 func createLogger() *zerolog.Logger {
@@ -81,15 +86,12 @@ func createLogger() *zerolog.Logger {
 
 func setupApiProvisioningStore() {
 	provisioningConfig := config.Current.Provisioning.Store
-	primaryStoreType := provisioningConfig.Primary.Type
-	secondaryStoreType := provisioningConfig.Secondary.Type
 
 	var err error
-	provisioningApiStore, err = store.SetupDualStoreManager("ProvisioningAPIStore", primaryStoreType, secondaryStoreType)
+	provisioningApiStore, err = store.SetupStoreManager("ProvisioningAPIStore", &provisioningConfig)
 	if err != nil {
 		log.Fatal().Fields(map[string]any{
-			"primaryStoreType":   primaryStoreType,
-			"secondaryStoreType": secondaryStoreType,
+			"stores": provisioningConfig.GetStores(),
 		}).Err(err).Msg("Could not create provisioning store manager!")
 	}
 }
@@ -5,9 +5,13 @@
 package provisioning
 
 import (
+	"github.com/telekom/quasar/internal/reconciliation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// degradedSourceHeader is set on read responses that have been served by a replica instead of the primary store.
+const degradedSourceHeader = "X-Quasar-Degraded-Source"
+
 // ResourceResponse represents the response for resource operations
 type ResourceResponse struct {
 	Resource *unstructured.Unstructured  `json:"resource,omitempty"`
@@ -16,6 +20,12 @@ type ResourceResponse struct {
 	Keys     []string                    `json:"keys,omitempty"`
 }
 
+// ReconciliationResponse represents the response for reconciliation operations
+type ReconciliationResponse struct {
+	Reports []*reconciliation.Report `json:"reports,omitempty"`
+	Results []*reconciliation.Result `json:"results,omitempty"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
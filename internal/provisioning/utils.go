@@ -5,8 +5,13 @@
 package provisioning
 
 import (
+	"errors"
+	"math"
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/store"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -30,6 +35,32 @@ func generateLogAttributes(operation string, id string, gvr schema.GroupVersionR
 	return result
 }
 
+// newStoreError returns a 503 with a Retry-After header if the circuit breaker of the store is open and a 500 otherwise.
+func newStoreError(ctx *fiber.Ctx, err error, message string) *fiber.Error {
+	if !errors.Is(err, store.ErrCircuitOpen) {
+		return &fiber.Error{
+			Code:    fiber.StatusInternalServerError,
+			Message: message,
+		}
+	}
+
+	var circuitOpenErr *store.CircuitOpenError
+	if errors.As(err, &circuitOpenErr) && circuitOpenErr.RetryAfter > 0 {
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(circuitOpenErr.RetryAfter.Seconds()))))
+	}
+	return &fiber.Error{
+		Code:    fiber.StatusServiceUnavailable,
+		Message: message + ": store is unavailable",
+	}
+}
+
+// setDegradedSourceHeader tells the client which replica served the request if the primary store is not connected.
+func setDegradedSourceHeader(ctx *fiber.Ctx, source *store.ReadSource) {
+	if source.Degraded {
+		ctx.Set(degradedSourceHeader, source.Store)
+	}
+}
+
 func getGvrFromContext(ctx *fiber.Ctx) (schema.GroupVersionResource, error) {
 	gvr, ok := ctx.Locals("gvr").(schema.GroupVersionResource)
 	if !ok || gvr.Version == "" || gvr.Resource == "" || gvr.Group == "" {
@@ -126,10 +157,18 @@ func getGvrAndIdFromContext(ctx *fiber.Ctx) (schema.GroupVersionResource, string
 }
 
 func getDataSetForGvr(gvr schema.GroupVersionResource) string {
+	if resourceConfig := getResourceConfigForGvr(gvr); resourceConfig != nil {
+		return resourceConfig.GetGroupVersionName()
+	}
+	return ""
+}
+
+// getResourceConfigForGvr returns the configuration of the resource or nil if it is not configured.
+func getResourceConfigForGvr(gvr schema.GroupVersionResource) *config.Resource {
 	for i, r := range config.Current.Resources {
 		k := r.Kubernetes
 		if k.Group == gvr.Group && k.Version == gvr.Version && k.Resource == gvr.Resource {
-			return config.Current.Resources[i].GetGroupVersionName()
+			return &config.Current.Resources[i]
 		}
 	}
 	logger.Warn().
@@ -137,5 +176,5 @@ func getDataSetForGvr(gvr schema.GroupVersionResource) string {
 		Str("version", gvr.Version).
 		Str("resource", gvr.Resource).
 		Msg("No Kubernetes configuration found for gvr")
-	return ""
+	return nil
 }
@@ -5,16 +5,18 @@
 package reconciliation
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // DataSource represents a source of truth for reconciliation
 type DataSource interface {
-	ListResources() ([]unstructured.Unstructured, error)
+	ListResources(ctx context.Context) ([]unstructured.Unstructured, error)
 }
 
 // Store provides minimal interface for listing resources from stores
 // This interface is satisfied by any store that implements List and prevents circular dependencies
 type Store interface {
-	List(dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error)
+	List(ctx context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error)
 }
@@ -6,13 +6,19 @@ package reconciliation
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/telekom/quasar/internal/config"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 )
 
+// NamespaceResource is the Kubernetes resource of namespaces, which are matched against namespace selectors.
+var NamespaceResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
 // KubernetesDataSource implements reconciliation's DataSource interface using Kubernetes Client
 type KubernetesDataSource struct {
 	client   dynamic.Interface
@@ -28,13 +34,47 @@ func NewDataSourceFromKubernetesClient(client dynamic.Interface, resource *confi
 }
 
 // ListResources retrieves all resources from Kubernetes Client relevant for reconciliation
-func (k *KubernetesDataSource) ListResources() ([]unstructured.Unstructured, error) {
-	resources, err := k.client.Resource(k.resource.GetGroupVersionResource()).
-		Namespace(k.resource.Kubernetes.Namespace).
-		List(context.Background(), v1.ListOptions{})
+func (k *KubernetesDataSource) ListResources(ctx context.Context) ([]unstructured.Unstructured, error) {
+	namespaces, err := k.listNamespaces(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return resources.Items, nil
+	listOptions := v1.ListOptions{}
+	k.resource.ApplySelectors(&listOptions)
+
+	items := make([]unstructured.Unstructured, 0)
+	for _, namespace := range namespaces {
+		resources, err := k.client.Resource(k.resource.GetGroupVersionResource()).
+			Namespace(namespace).
+			List(ctx, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resources.Items...)
+	}
+
+	return items, nil
+}
+
+// listNamespaces returns the namespaces the resource is watched in, including the ones currently matching its
+// namespace selector.
+func (k *KubernetesDataSource) listNamespaces(ctx context.Context) ([]string, error) {
+	namespaces := k.resource.GetNamespaces()
+	if k.resource.Kubernetes.NamespaceSelector == "" {
+		return namespaces, nil
+	}
+
+	selected, err := k.client.Resource(NamespaceResource).List(ctx, v1.ListOptions{
+		LabelSelector: k.resource.Kubernetes.NamespaceSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list namespaces: %w", err)
+	}
+
+	for _, namespace := range selected.Items {
+		namespaces = append(namespaces, namespace.GetName())
+	}
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces), nil
 }
@@ -6,6 +6,11 @@ package reconciliation
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,101 +18,167 @@ import (
 	"github.com/telekom/quasar/internal/config"
 	"github.com/telekom/quasar/internal/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const minReconciliationInterval = 60 * time.Second
+
+var (
+	ErrReconciliationDisabled   = errors.New("reconciliation is disabled for the resource")
+	ErrReconciliationInProgress = errors.New("reconciliation is already in progress")
 )
 
 type Reconciliation struct {
-	dataSource DataSource
-	resource   *config.Resource
-	mu         sync.Mutex
+	dataSource  DataSource
+	resource    *config.Resource
+	defaultMode config.ReconcileMode
+	mu          sync.Mutex
 }
 
 type Reconcilable interface {
-	Create(obj *unstructured.Unstructured) error
-	Count(mapName string) (int, error)
-	Keys(mapName string) ([]string, error)
+	Create(ctx context.Context, obj *unstructured.Unstructured) error
+	Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error
+	Delete(ctx context.Context, obj *unstructured.Unstructured) error
+	List(ctx context.Context, mapName string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error)
 	Connected() bool
 }
 
-func NewReconciliation(dataSource DataSource, resource *config.Resource) *Reconciliation {
+// Result counts the entries a reconciliation has added to, updated in and removed from a store.
+// Orphaned entries that were not removed because of the safety threshold are counted as skipped.
+type Result struct {
+	Dataset        string `json:"dataset"`
+	Store          string `json:"store,omitempty"`
+	Added          int    `json:"added"`
+	Updated        int    `json:"updated"`
+	Removed        int    `json:"removed"`
+	SkippedOrphans int    `json:"skippedOrphans"`
+}
+
+// NewReconciliation creates a reconciliation of the resource with the data source. The default mode is used unless
+// the resource configures its own.
+func NewReconciliation(dataSource DataSource, resource *config.Resource, defaultMode config.ReconcileMode) *Reconciliation {
 	return &Reconciliation{
-		dataSource: dataSource,
-		resource:   resource,
+		dataSource:  dataSource,
+		resource:    resource,
+		defaultMode: defaultMode,
 	}
 }
 
-func (r *Reconciliation) reconcile(reconcilable Reconcilable) {
-	resources, err := r.dataSource.ListResources()
+func (r *Reconciliation) reconcile(ctx context.Context, reconcilable Reconcilable) (*Result, error) {
+	resources, stored, err := r.list(ctx, reconcilable)
 	if err != nil {
 		log.Error().Err(err).Fields(map[string]any{
 			"cache": r.resource.GetGroupVersionName(),
-		}).Msg("Could not retrieve resources from data source")
-		return
+		}).Msg("Could not reconcile")
+		return nil, err
 	}
 
-	mode := config.Current.Store.Hazelcast.ReconcileMode
+	mode := r.resource.Reconciliation.GetMode(r.defaultMode)
+	result := &Result{Dataset: r.resource.GetGroupVersionName()}
 
 	switch mode {
 	case config.ReconcileModeFull:
-		r.fullyReconcile(reconcilable, resources)
+		r.fullyReconcile(ctx, reconcilable, resources, stored, result)
 
 	case config.ReconcileModeIncremental:
-		r.incrementallyReconcile(reconcilable, resources)
+		r.incrementallyReconcile(ctx, reconcilable, resources, stored, result)
 
 	default:
 		log.Error().
 			Str("cache", r.resource.GetGroupVersionName()).
 			Str("mode", mode.String()).
 			Msg("Unknown reconciliation mode, skipping")
+		return nil, fmt.Errorf("unknown reconciliation mode %q", mode)
 	}
+
+	r.removeOrphans(ctx, reconcilable, resources, stored, result)
+
+	event := log.Debug()
+	if result.Added > 0 || result.Updated > 0 || result.Removed > 0 || result.SkippedOrphans > 0 {
+		event = log.Info()
+	}
+	event.Str("cache", r.resource.GetGroupVersionName()).
+		Str("mode", mode.String()).
+		Int("added", result.Added).
+		Int("updated", result.Updated).
+		Int("removed", result.Removed).
+		Int("skippedOrphans", result.SkippedOrphans).
+		Msg("Reconciliation finished")
+	return result, nil
 }
 
-func (r *Reconciliation) incrementallyReconcile(reconcilable Reconcilable, resources []unstructured.Unstructured) {
-	resourceCount := len(resources)
-	storeSize, err := reconcilable.Count(r.resource.GetGroupVersionName())
+// list returns the resources of the data source and the entries of the store by name.
+func (r *Reconciliation) list(ctx context.Context, store Store) ([]unstructured.Unstructured, map[string]*unstructured.Unstructured, error) {
+	resources, err := r.dataSource.ListResources(ctx)
 	if err != nil {
-		log.Error().Err(err).Fields(map[string]any{
-			"cache": r.resource.GetGroupVersionName(),
-		}).Msg("Could not get size of store")
-		return
+		return nil, nil, fmt.Errorf("could not retrieve resources from data source: %w", err)
 	}
 
-	log.Info().Fields(map[string]any{
-		"cache":         r.resource.GetGroupVersionName(),
-		"storeSize":     storeSize,
-		"resourceCount": resourceCount,
-	}).Msg("Checking for store size mismatch...")
+	storeItems, err := store.List(ctx, r.resource.GetGroupVersionName(), "", 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve store entries: %w", err)
+	}
+	return resources, byName(storeItems), nil
+}
 
-	if storeSize < resourceCount {
-		log.Warn().Fields(map[string]any{
-			"cache": r.resource.GetGroupVersionName(),
-		}).Msg("Store size does not match resource count. Generating diff for reconciliation...")
+// incrementallyReconcile creates missing entries and rewrites entries whose resource version or, if missing,
+// content differs from the data source.
+func (r *Reconciliation) incrementallyReconcile(
+	ctx context.Context,
+	reconcilable Reconcilable,
+	resources []unstructured.Unstructured,
+	stored map[string]*unstructured.Unstructured,
+	result *Result,
+) {
+	missingItems, staleItems := r.generateDiff(resources, stored)
+	if len(missingItems) == 0 && len(staleItems) == 0 {
+		return
+	}
 
-		storeKeys, err := reconcilable.Keys(r.resource.GetGroupVersionName())
-		if err != nil {
-			log.Error().Err(err).Msg("Could no retrieve store keys")
+	log.Warn().Msgf("Identified %d missing and %d stale cache entries. Reprocessing...", len(missingItems), len(staleItems))
+	for _, item := range missingItems {
+		if err := reconcilable.Create(ctx, item); err != nil {
+			log.Error().Err(err).Fields(utils.CreateFieldsForOp("restore", item)).Msg("Failed to reconcile (diff) item")
+			continue
 		}
+		result.Added++
+		log.Warn().Fields(utils.CreateFieldsForOp("restore", item)).Msg("Reconciled (diff) item")
+	}
 
-		missingItems := r.generateDiff(resources, storeKeys)
-		log.Warn().Msgf("Identified %d missing cache entries. Reprocessing...", len(missingItems))
-		for _, item := range missingItems {
-			utils.AddMissingEnvironment(&item)
-			if err := reconcilable.Create(&item); err != nil {
-				log.Error().Err(err).Fields(utils.CreateFieldsForOp("restore", &item)).Msg("Failed to reconcile (diff) item")
-			}
-			log.Warn().Fields(utils.CreateFieldsForOp("restore", &item)).Msg("Reconciled (diff) item")
+	for _, item := range staleItems {
+		if err := reconcilable.Update(ctx, stored[item.GetName()], item); err != nil {
+			log.Error().Err(err).Fields(utils.CreateFieldsForOp("refresh", item)).Msg("Failed to reconcile (diff) item")
+			continue
 		}
+		result.Updated++
+		log.Warn().Fields(utils.CreateFieldsForOp("refresh", item)).Msg("Reconciled (diff) item")
 	}
 }
 
-func (r *Reconciliation) fullyReconcile(reconcilable Reconcilable, resources []unstructured.Unstructured) {
+func (r *Reconciliation) fullyReconcile(
+	ctx context.Context,
+	reconcilable Reconcilable,
+	resources []unstructured.Unstructured,
+	stored map[string]*unstructured.Unstructured,
+	result *Result,
+) {
 	log.Debug().
 		Str("cache", r.resource.GetGroupVersionName()).
 		Int("count", len(resources)).
 		Msg("Performing full reconciliation: inserting all resources")
+
 	for _, item := range resources {
 		utils.AddMissingEnvironment(&item)
-		if err := reconcilable.Create(&item); err != nil {
+		if err := reconcilable.Create(ctx, &item); err != nil {
 			log.Error().Err(err).Fields(utils.CreateFieldsForOp("create", &item)).Msg("Failed to reconcile (full) item")
+			continue
+		}
+
+		if current, ok := stored[item.GetName()]; !ok {
+			result.Added++
+		} else if !equivalent(current, &item) {
+			result.Updated++
 		}
 		log.Debug().
 			Fields(utils.CreateFieldsForOp("create", &item)).
@@ -115,45 +186,202 @@ func (r *Reconciliation) fullyReconcile(reconcilable Reconcilable, resources []u
 	}
 }
 
-func (r *Reconciliation) generateDiff(resources []unstructured.Unstructured, storeKeys []string) []unstructured.Unstructured {
-	diff := make([]unstructured.Unstructured, 0)
-	for _, resource := range resources {
-		found := false
-		for _, storeKey := range storeKeys {
-			if resource.GetName() == storeKey {
-				found = true
-				break
-			}
+// removeOrphans deletes store entries of the resource that do not exist in the data source anymore.
+// The deletion is skipped if the data source returned no resources at all or if more than the configured share
+// of the entries would be deleted, as both usually indicate a problem with the data source rather than mass deletion.
+func (r *Reconciliation) removeOrphans(
+	ctx context.Context,
+	reconcilable Reconcilable,
+	resources []unstructured.Unstructured,
+	stored map[string]*unstructured.Unstructured,
+	result *Result,
+) {
+	reconciliationConfig := &config.Current.Reconciliation
+	if !r.resource.Reconciliation.ShouldDeleteOrphans(reconciliationConfig.DeleteOrphans) {
+		return
+	}
+
+	managed := r.managed(stored)
+	orphans := findOrphans(resources, managed)
+	if len(orphans) == 0 {
+		return
+	}
+
+	ratio := float64(len(orphans)) / float64(len(managed))
+	if len(resources) == 0 || ratio > reconciliationConfig.OrphanThreshold {
+		result.SkippedOrphans = len(orphans)
+		log.Warn().
+			Str("cache", r.resource.GetGroupVersionName()).
+			Int("orphans", len(orphans)).
+			Int("storeSize", len(stored)).
+			Int("resourceCount", len(resources)).
+			Float64("threshold", reconciliationConfig.OrphanThreshold).
+			Msg("Too many orphaned cache entries, skipping deletion")
+		return
+	}
+
+	for _, key := range orphans {
+		orphan := r.orphan(stored[key])
+		if err := reconcilable.Delete(ctx, orphan); err != nil {
+			log.Error().Err(err).Fields(utils.CreateFieldsForOp("remove", orphan)).Msg("Failed to remove orphaned item")
+			continue
 		}
+		result.Removed++
+		log.Warn().Fields(utils.CreateFieldsForOp("remove", orphan)).Msg("Removed orphaned item")
+	}
+}
 
-		if !found {
-			diff = append(diff, resource)
+// findOrphans returns the sorted keys of the store entries that do not exist in the data source.
+func findOrphans(resources []unstructured.Unstructured, stored map[string]*unstructured.Unstructured) []string {
+	names := byName(resources)
+	orphans := make([]string, 0)
+	for key := range stored {
+		if _, ok := names[key]; !ok {
+			orphans = append(orphans, key)
 		}
 	}
+	slices.Sort(orphans)
+	return orphans
+}
 
-	return diff
+// managed returns the store entries of the resource. Entries of the dataset in namespaces the resource is not watched in
+// or not matching its label and field selectors belong to other resources and must not be removed as orphans.
+// With a namespace selector, the entries of all namespaces belong to the resource, so that the entries of namespaces
+// that do not match the selector anymore are removed.
+func (r *Reconciliation) managed(stored map[string]*unstructured.Unstructured) map[string]*unstructured.Unstructured {
+	kubernetes := r.resource.Kubernetes
+	labelSelector, err := labels.Parse(kubernetes.LabelSelector)
+	if err != nil {
+		log.Error().Err(err).Str("cache", r.resource.GetGroupVersionName()).Msg("Invalid label selector, keeping all entries")
+		return nil
+	}
+	fieldSelector, err := fields.ParseSelector(kubernetes.FieldSelector)
+	if err != nil {
+		log.Error().Err(err).Str("cache", r.resource.GetGroupVersionName()).Msg("Invalid field selector, keeping all entries")
+		return nil
+	}
+
+	var namespaces []string
+	if kubernetes.NamespaceSelector == "" && (kubernetes.Namespace != "" || r.resource.HasMultipleNamespaces()) {
+		namespaces = r.resource.GetNamespaces()
+	}
+
+	managed := make(map[string]*unstructured.Unstructured, len(stored))
+	for key, entry := range stored {
+		namespace := entry.GetNamespace()
+		if namespace == "" {
+			namespace = kubernetes.Namespace
+		}
+		if namespaces != nil && !slices.Contains(namespaces, namespace) {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(entry.GetLabels())) || !fieldSelector.Matches(selectedFields(entry, fieldSelector)) {
+			continue
+		}
+		managed[key] = entry
+	}
+	return managed
+}
+
+// selectedFields returns the values of the fields of the entry the selector refers to.
+func selectedFields(entry *unstructured.Unstructured, selector fields.Selector) fields.Set {
+	set := make(fields.Set)
+	for _, requirement := range selector.Requirements() {
+		value, found, err := unstructured.NestedFieldNoCopy(entry.Object, strings.Split(requirement.Field, ".")...)
+		if found && err == nil {
+			set[requirement.Field] = fmt.Sprint(value)
+		}
+	}
+	return set
+}
+
+// orphan returns the store entry to delete. The entry itself is deleted, as stores identify entries by their uid
+// or the configured mongoId rather than by name.
+func (r *Reconciliation) orphan(entry *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := entry.DeepCopy()
+	obj.SetGroupVersionKind(r.resource.GetGroupVersionKind())
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(r.resource.Kubernetes.Namespace)
+	}
+	return obj
+}
+
+// generateDiff returns the resources that are missing in the store and the resources whose store entries are stale.
+func (r *Reconciliation) generateDiff(
+	resources []unstructured.Unstructured,
+	stored map[string]*unstructured.Unstructured,
+) ([]*unstructured.Unstructured, []*unstructured.Unstructured) {
+	missing := make([]*unstructured.Unstructured, 0)
+	stale := make([]*unstructured.Unstructured, 0)
+	for i := range resources {
+		resource := &resources[i]
+		utils.AddMissingEnvironment(resource)
+
+		current, ok := stored[resource.GetName()]
+		if !ok {
+			missing = append(missing, resource)
+			continue
+		}
+		if !equivalent(current, resource) {
+			stale = append(stale, resource)
+		}
+	}
+
+	return missing, stale
+}
+
+// equivalent returns whether the store entry matches the resource of the data source.
+// Entries are written with the environment, so a missing environment must not make them differ from the resource.
+func equivalent(entry *unstructured.Unstructured, resource *unstructured.Unstructured) bool {
+	utils.AddMissingEnvironment(entry)
+	return utils.Equivalent(entry, resource)
+}
+
+func byName(items []unstructured.Unstructured) map[string]*unstructured.Unstructured {
+	named := make(map[string]*unstructured.Unstructured, len(items))
+	for i := range items {
+		named[items[i].GetName()] = &items[i]
+	}
+	return named
 }
 
 // StartPeriodicReconcile starts a blocking periodic reconciliation process that runs at the specified interval.
+// The interval and jitter configured for the resource take precedence over the given interval.
 func (r *Reconciliation) StartPeriodicReconcile(ctx context.Context, interval time.Duration, reconcilable Reconcilable) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	if !r.resource.Reconciliation.IsEnabled() {
+		log.Debug().
+			Str("cache", r.resource.GetGroupVersionName()).
+			Msg("Reconciliation is disabled, not starting periodic reconciliation")
+		return
+	}
+
+	interval = r.resource.Reconciliation.GetInterval(interval)
+	if interval < minReconciliationInterval {
+		log.Warn().Msg("Reconciliation interval is set to less than 60 seconds. Setting it to 60 seconds.")
+		interval = minReconciliationInterval
+	}
+	jitter := r.resource.Reconciliation.Jitter
 
 	log.Debug().
 		Dur("interval", interval).
+		Dur("jitter", jitter).
 		Str("cache", r.resource.GetGroupVersionName()).
 		Msg("Starting periodic reconciliation")
 
+	timer := time.NewTimer(withJitter(interval, jitter))
+	defer timer.Stop()
+
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
+			timer.Reset(withJitter(interval, jitter))
 			if !reconcilable.Connected() {
 				log.Debug().
 					Str("cache", r.resource.GetGroupVersionName()).
-					Msg("Skipping timed reconciliation: Hazelcast client disconnected")
+					Msg("Skipping timed reconciliation: store disconnected")
 				continue
 			}
-			r.SafeReconcile(reconcilable)
+			r.SafeReconcile(ctx, reconcilable)
 		case <-ctx.Done():
 			log.Debug().
 				Str("cache", r.resource.GetGroupVersionName()).
@@ -163,16 +391,34 @@ func (r *Reconciliation) StartPeriodicReconcile(ctx context.Context, interval ti
 	}
 }
 
-func (r *Reconciliation) SafeReconcile(reconcilable Reconcilable) {
+// withJitter adds a random duration of up to jitter to the interval, so that reconciliations of several instances
+// or resources do not hit the stores at the same time.
+func withJitter(interval time.Duration, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + rand.N(jitter)
+}
+
+// SafeReconcile reconciles the store with the data source unless reconciliation is disabled for the resource or
+// already in progress. Panics during the reconciliation are recovered and returned as error.
+func (r *Reconciliation) SafeReconcile(ctx context.Context, reconcilable Reconcilable) (result *Result, err error) {
 	log.Debug().
 		Str("cache", r.resource.GetGroupVersionName()).
 		Msg("Starting safe reconciliation")
 
+	if !r.resource.Reconciliation.IsEnabled() {
+		log.Debug().
+			Str("cache", r.resource.GetGroupVersionName()).
+			Msg("Reconciliation is disabled, skipping")
+		return nil, ErrReconciliationDisabled
+	}
+
 	if !r.mu.TryLock() {
 		log.Warn().
 			Str("cache", r.resource.GetGroupVersionName()).
 			Msg("Reconciliation already in progress, skipping")
-		return
+		return nil, ErrReconciliationInProgress
 	}
 	defer r.mu.Unlock()
 
@@ -182,8 +428,9 @@ func (r *Reconciliation) SafeReconcile(reconcilable Reconcilable) {
 				Str("cache", r.resource.GetGroupVersionName()).
 				Interface("panic", rec).
 				Msg("Recovered from panic during reconciliation")
+			err = fmt.Errorf("panic during reconciliation: %v", rec)
 		}
 	}()
 
-	r.reconcile(reconcilable)
+	return r.reconcile(ctx, reconcilable)
 }
@@ -0,0 +1,427 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package reconciliation_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestMain(m *testing.M) {
+	test.InstallLogRecorder()
+	os.Exit(m.Run())
+}
+
+// staticDataSource is a data source that always returns the same resources.
+type staticDataSource []unstructured.Unstructured
+
+func (d staticDataSource) ListResources(context.Context) ([]unstructured.Unstructured, error) {
+	return d, nil
+}
+
+// mapReconcilable is a reconcilable that keeps its entries in a map.
+type mapReconcilable struct {
+	mu      sync.Mutex
+	entries map[string]*unstructured.Unstructured
+}
+
+func newMapReconcilable(objs ...*unstructured.Unstructured) *mapReconcilable {
+	reconcilable := &mapReconcilable{entries: make(map[string]*unstructured.Unstructured)}
+	for _, obj := range objs {
+		reconcilable.entries[obj.GetName()] = obj
+	}
+	return reconcilable
+}
+
+func (m *mapReconcilable) Create(_ context.Context, obj *unstructured.Unstructured) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[obj.GetName()] = obj.DeepCopy()
+	return nil
+}
+
+func (m *mapReconcilable) Update(ctx context.Context, _ *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	return m.Create(ctx, newObj)
+}
+
+func (m *mapReconcilable) Delete(_ context.Context, obj *unstructured.Unstructured) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, obj.GetName())
+	return nil
+}
+
+func (m *mapReconcilable) Keys(context.Context, string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.entries))
+	for key := range m.entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func (m *mapReconcilable) List(context.Context, string, string, int64) ([]unstructured.Unstructured, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := make([]unstructured.Unstructured, 0, len(m.entries))
+	for _, obj := range m.entries {
+		items = append(items, *obj.DeepCopy())
+	}
+	return items, nil
+}
+
+func (m *mapReconcilable) Read(name string) *unstructured.Unstructured {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entries[name]
+}
+
+func (m *mapReconcilable) Connected() bool {
+	return true
+}
+
+// idReconcilable is a reconcilable that deletes entries by their mongo id like the mongo, file and postgres stores.
+type idReconcilable struct {
+	*mapReconcilable
+}
+
+func (r idReconcilable) Delete(_ context.Context, obj *unstructured.Unstructured) error {
+	id, err := utils.GetMongoId(obj)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, entry := range r.entries {
+		if entryId, _ := utils.GetMongoId(entry); entryId == id {
+			delete(r.entries, name)
+		}
+	}
+	return nil
+}
+
+func setupReconciliationTest(mode config.ReconcileMode) (*config.Resource, []*unstructured.Unstructured) {
+	testConfig := test.BuildBaseTestConfig()
+	testConfig.Reconciliation.Mode = mode
+	test.AddTestResource(testConfig, "subscriber.horizon.telekom.de", "v1", "subscriptions", "Subscription", "playground")
+	config.Current = testConfig
+
+	return &testConfig.Resources[0], test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+}
+
+func orphanOf(obj *unstructured.Unstructured, name string) *unstructured.Unstructured {
+	orphan := obj.DeepCopy()
+	orphan.SetName(name)
+	return orphan
+}
+
+// TestReconciliation_RemovesOrphans tests that missing resources are added and orphaned entries are removed
+func TestReconciliation_RemovesOrphans(t *testing.T) {
+	for _, mode := range []config.ReconcileMode{config.ReconcileModeIncremental, config.ReconcileModeFull} {
+		t.Run(mode.String(), func(t *testing.T) {
+			assertions := assert.New(t)
+			defer test.LogRecorder.Reset()
+
+			resourceConfig, subscriptions := setupReconciliationTest(mode)
+			dataSource := staticDataSource{*subscriptions[0], *subscriptions[1]}
+			reconcilable := newMapReconcilable(subscriptions[0], orphanOf(subscriptions[0], "orphaned-subscription"))
+
+			result, err := reconciliation.NewReconciliation(dataSource, resourceConfig, config.Current.Reconciliation.Mode).SafeReconcile(context.Background(), reconcilable)
+			assertions.NoError(err)
+			assertions.Equal(&reconciliation.Result{Dataset: resourceConfig.GetGroupVersionName(), Added: 1, Removed: 1}, result)
+
+			keys, _ := reconcilable.Keys(context.Background(), resourceConfig.GetGroupVersionName())
+			assertions.ElementsMatch([]string{subscriptions[0].GetName(), subscriptions[1].GetName()}, keys)
+		})
+	}
+}
+
+// TestReconciliation_OrphanThreshold tests that orphaned entries are kept if too many of them would be removed
+func TestReconciliation_OrphanThreshold(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeIncremental)
+	orphans := []*unstructured.Unstructured{
+		orphanOf(subscriptions[0], "orphaned-subscription-1"),
+		orphanOf(subscriptions[0], "orphaned-subscription-2"),
+	}
+
+	reconcilable := newMapReconcilable(append(orphans, subscriptions[0])...)
+	recon := reconciliation.NewReconciliation(staticDataSource{*subscriptions[0]}, resourceConfig, config.Current.Reconciliation.Mode)
+	recon.SafeReconcile(context.Background(), reconcilable)
+	keys, _ := reconcilable.Keys(context.Background(), resourceConfig.GetGroupVersionName())
+	assertions.Len(keys, 3, "orphans exceeding the threshold should not be removed")
+
+	reconcilable = newMapReconcilable(subscriptions[0], subscriptions[1])
+	recon = reconciliation.NewReconciliation(staticDataSource{}, resourceConfig, config.Current.Reconciliation.Mode)
+	config.Current.Reconciliation.OrphanThreshold = 1
+	recon.SafeReconcile(context.Background(), reconcilable)
+	keys, _ = reconcilable.Keys(context.Background(), resourceConfig.GetGroupVersionName())
+	assertions.Len(keys, 2, "entries should not be removed if the data source is empty")
+
+	config.Current.Reconciliation.DeleteOrphans = false
+	reconcilable = newMapReconcilable(subscriptions[0], orphans[0])
+	recon = reconciliation.NewReconciliation(staticDataSource{*subscriptions[0]}, resourceConfig, config.Current.Reconciliation.Mode)
+	recon.SafeReconcile(context.Background(), reconcilable)
+	keys, _ = reconcilable.Keys(context.Background(), resourceConfig.GetGroupVersionName())
+	assertions.Len(keys, 2, "orphans should not be removed if disabled")
+}
+
+
+// TestReconciliation_OrphanScope tests that only orphaned entries in the namespaces and matching the selectors of the
+// resource are removed
+func TestReconciliation_OrphanScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(resourceConfig *config.Resource)
+		removed []string
+	}{
+		{"namespace", func(*config.Resource) {}, []string{"labeled", "unlabeled"}},
+		{"all namespaces", func(resourceConfig *config.Resource) {
+			resourceConfig.Kubernetes.Namespace = ""
+		}, []string{"labeled", "other-namespace", "unlabeled"}},
+		{"namespaces", func(resourceConfig *config.Resource) {
+			resourceConfig.Kubernetes.Namespaces = []string{"integration"}
+		}, []string{"labeled", "other-namespace", "unlabeled"}},
+		{"namespace selector", func(resourceConfig *config.Resource) {
+			resourceConfig.Kubernetes.NamespaceSelector = "quasar=enabled"
+		}, []string{"labeled", "other-namespace", "unlabeled"}},
+		{"label selector", func(resourceConfig *config.Resource) {
+			resourceConfig.Kubernetes.LabelSelector = "environment=playground"
+		}, []string{"labeled"}},
+		{"field selector", func(resourceConfig *config.Resource) {
+			resourceConfig.Kubernetes.FieldSelector = "metadata.name!=unlabeled"
+		}, []string{"labeled"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions := assert.New(t)
+			defer test.LogRecorder.Reset()
+
+			resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeIncremental)
+			config.Current.Reconciliation.OrphanThreshold = 1
+			tt.modify(resourceConfig)
+
+			labeled := orphanOf(subscriptions[0], "labeled")
+			labeled.SetLabels(map[string]string{"environment": "playground"})
+			otherNamespace := orphanOf(subscriptions[0], "other-namespace")
+			otherNamespace.SetNamespace("integration")
+			reconcilable := newMapReconcilable(subscriptions[0], labeled, orphanOf(subscriptions[0], "unlabeled"), otherNamespace)
+
+			recon := reconciliation.NewReconciliation(staticDataSource{*subscriptions[0]}, resourceConfig, config.Current.Reconciliation.Mode)
+			result, err := recon.SafeReconcile(context.Background(), reconcilable)
+			assertions.NoError(err)
+			assertions.Equal(len(tt.removed), result.Removed)
+			for _, name := range tt.removed {
+				assertions.Nil(reconcilable.Read(name), "orphans of the resource should be removed")
+			}
+			assertions.NotNil(reconcilable.Read(subscriptions[0].GetName()))
+		})
+	}
+}
+
+// TestReconciliation_RefreshesStaleEntries tests that incremental reconciliation rewrites only changed entries
+func TestReconciliation_RefreshesStaleEntries(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeIncremental)
+
+	updated := subscriptions[0].DeepCopy()
+	updated.SetResourceVersion("2")
+	unchanged := subscriptions[1].DeepCopy()
+	unchanged.SetResourceVersion("")
+	changed := unchanged.DeepCopy()
+	changed.SetLabels(map[string]string{"reconciliation_test": "true"})
+
+	stale := subscriptions[0].DeepCopy()
+	stale.SetResourceVersion("1")
+	reconcilable := newMapReconcilable(stale, unchanged)
+
+	recon := reconciliation.NewReconciliation(staticDataSource{*updated, *unchanged}, resourceConfig, config.Current.Reconciliation.Mode)
+	recon.SafeReconcile(context.Background(), reconcilable)
+	assertions.Equal("2", reconcilable.Read(updated.GetName()).GetResourceVersion(), "entries with another resource version should be rewritten")
+	assertions.Same(unchanged, reconcilable.Read(unchanged.GetName()), "unchanged entries should not be rewritten")
+
+	recon = reconciliation.NewReconciliation(staticDataSource{*updated, *changed}, resourceConfig, config.Current.Reconciliation.Mode)
+	recon.SafeReconcile(context.Background(), reconcilable)
+	assertions.Equal("true", reconcilable.Read(changed.GetName()).GetLabels()["reconciliation_test"],
+		"entries without resource version should be compared by content")
+}
+
+// TestReconciliation_ResourceSettings tests that the reconciliation settings of a resource override the global ones
+func TestReconciliation_ResourceSettings(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeFull)
+	orphan := orphanOf(subscriptions[0], "orphaned-subscription")
+
+	deleteOrphans := false
+	resourceConfig.Reconciliation.DeleteOrphans = &deleteOrphans
+	reconcilable := newMapReconcilable(subscriptions[0], orphan)
+	recon := reconciliation.NewReconciliation(staticDataSource{*subscriptions[0], *subscriptions[1]}, resourceConfig, config.Current.Reconciliation.Mode)
+	recon.SafeReconcile(context.Background(), reconcilable)
+	assertions.NotNil(reconcilable.Read(orphan.GetName()), "orphans should be kept if disabled for the resource")
+	assertions.NotNil(reconcilable.Read(subscriptions[1].GetName()))
+
+	resourceConfig.Reconciliation.Mode = config.ReconcileModeIncremental
+	reconcilable = newMapReconcilable(subscriptions[0])
+	recon.SafeReconcile(context.Background(), reconcilable)
+	assertions.Same(subscriptions[0], reconcilable.Read(subscriptions[0].GetName()),
+		"unchanged entries should not be rewritten in incremental mode of the resource")
+
+	enabled := false
+	resourceConfig.Reconciliation.Enabled = &enabled
+	reconcilable = newMapReconcilable()
+	_, err := recon.SafeReconcile(context.Background(), reconcilable)
+	assertions.ErrorIs(err, reconciliation.ErrReconciliationDisabled)
+	assertions.Nil(reconcilable.Read(subscriptions[0].GetName()), "resources with disabled reconciliation should not be reconciled")
+}
+
+// TestReconciliation_DryRun tests that a dry run reports the differences without resolving them
+func TestReconciliation_DryRun(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeFull)
+
+	stale := subscriptions[0].DeepCopy()
+	stale.SetResourceVersion("1")
+	stale.SetLabels(map[string]string{"reconciliation_test": "true"})
+	updated := subscriptions[0].DeepCopy()
+	updated.SetResourceVersion("2")
+	orphan := orphanOf(subscriptions[0], "orphaned-subscription")
+	reconcilable := newMapReconcilable(stale, orphan)
+
+	recon := reconciliation.NewReconciliation(staticDataSource{*updated, *subscriptions[1]}, resourceConfig, config.Current.Reconciliation.Mode)
+	report, err := recon.DryRun(context.Background(), reconcilable)
+	assertions.NoError(err)
+	assertions.False(report.Consistent())
+	assertions.Equal(resourceConfig.GetGroupVersionName(), report.Dataset)
+	assertions.Equal([]string{subscriptions[1].GetName()}, report.Missing)
+	assertions.Equal([]string{updated.GetName()}, report.Stale)
+	assertions.Equal([]string{orphan.GetName()}, report.Orphaned)
+
+	if assertions.Len(report.Samples, 1) {
+		assertions.Equal(updated.GetName(), report.Samples[0].Key)
+		assertions.ElementsMatch([]reconciliation.Change{
+			{Path: "metadata.labels", Stored: map[string]any{"reconciliation_test": "true"}, Expected: nil},
+			{Path: "metadata.resourceVersion", Stored: "1", Expected: "2"},
+		}, report.Samples[0].Changes)
+	}
+
+	keys, _ := reconcilable.Keys(context.Background(), resourceConfig.GetGroupVersionName())
+	assertions.ElementsMatch([]string{orphan.GetName(), stale.GetName()}, keys, "dry run should not write anything")
+	assertions.Same(stale, reconcilable.Read(stale.GetName()))
+}
+
+// TestKubernetesDataSource_Selectors tests that only resources matching the selectors of the resource are listed
+func TestKubernetesDataSource_Selectors(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeFull)
+	selected := subscriptions[0].DeepCopy()
+	selected.SetLabels(map[string]string{"environment": "playground"})
+	other := subscriptions[1].DeepCopy()
+	other.SetLabels(map[string]string{"environment": "integration"})
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), selected, other)
+
+	resourceConfig.Kubernetes.Namespace = selected.GetNamespace()
+	resourceConfig.Kubernetes.LabelSelector = "environment=playground"
+	resources, err := reconciliation.NewDataSourceFromKubernetesClient(client, resourceConfig).ListResources(context.Background())
+	assertions.NoError(err)
+	if assertions.Len(resources, 1) {
+		assertions.Equal(selected.GetName(), resources[0].GetName())
+	}
+}
+
+// TestKubernetesDataSource_Namespaces tests that resources are listed across the configured and selected namespaces
+func TestKubernetesDataSource_Namespaces(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeFull)
+	selectedNamespace := new(unstructured.Unstructured)
+	selectedNamespace.SetAPIVersion("v1")
+	selectedNamespace.SetKind("Namespace")
+	selectedNamespace.SetName("playground")
+	selectedNamespace.SetLabels(map[string]string{"quasar": "enabled"})
+	otherNamespace := selectedNamespace.DeepCopy()
+	otherNamespace.SetName("integration")
+	otherNamespace.SetLabels(nil)
+
+	inNamespace := func(obj *unstructured.Unstructured, namespace string) *unstructured.Unstructured {
+		namespaced := obj.DeepCopy()
+		namespaced.SetNamespace(namespace)
+		return namespaced
+	}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(),
+		selectedNamespace,
+		otherNamespace,
+		inNamespace(subscriptions[0], "playground"),
+		inNamespace(subscriptions[1], "integration"),
+		inNamespace(subscriptions[1], "static"),
+	)
+
+	resourceConfig.Kubernetes.Namespaces = []string{"static"}
+	resourceConfig.Kubernetes.NamespaceSelector = "quasar=enabled"
+	resources, err := reconciliation.NewDataSourceFromKubernetesClient(client, resourceConfig).ListResources(context.Background())
+	assertions.NoError(err)
+
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.GetNamespace()+"/"+resource.GetName())
+	}
+	assertions.ElementsMatch([]string{
+		"playground/" + subscriptions[0].GetName(),
+		"static/" + subscriptions[1].GetName(),
+	}, names)
+}
+
+// TestReconciliation_RemovesOrphansById tests that orphans are removed from stores that delete entries by their id
+func TestReconciliation_RemovesOrphansById(t *testing.T) {
+	for _, mongoId := range []string{"", "metadata.name"} {
+		t.Run("mongoId="+mongoId, func(t *testing.T) {
+			assertions := assert.New(t)
+			defer test.LogRecorder.Reset()
+
+			resourceConfig, subscriptions := setupReconciliationTest(config.ReconcileModeIncremental)
+			config.Current.Resources[0].MongoId = mongoId
+			orphan := orphanOf(subscriptions[1], "orphaned-subscription")
+			orphan.SetUID("orphaned-uid")
+			reconcilable := idReconcilable{newMapReconcilable(subscriptions[0], subscriptions[1], orphan)}
+
+			recon := reconciliation.NewReconciliation(staticDataSource{*subscriptions[0], *subscriptions[1]}, resourceConfig, config.Current.Reconciliation.Mode)
+			result, err := recon.SafeReconcile(context.Background(), reconcilable)
+			assertions.NoError(err)
+			assertions.Equal(1, result.Removed)
+			assertions.Nil(reconcilable.Read(orphan.GetName()), "orphans should be deleted by their id")
+			assertions.NotNil(reconcilable.Read(subscriptions[1].GetName()))
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reconciliation
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxReportSamples limits the number of stale entries whose differences are included in a report.
+const maxReportSamples = 10
+
+// Report lists the differences between a store and the data source that a reconciliation would resolve.
+// Keys are the names of the resources.
+type Report struct {
+	Dataset  string   `json:"dataset"`
+	Store    string   `json:"store,omitempty"`
+	Missing  []string `json:"missing"`
+	Stale    []string `json:"stale"`
+	Orphaned []string `json:"orphaned"`
+	Samples  []Sample `json:"samples"`
+}
+
+// Sample shows in which fields a stale store entry differs from its resource.
+type Sample struct {
+	Key     string   `json:"key"`
+	Changes []Change `json:"changes"`
+}
+
+// Change is a field whose value in the store differs from the data source. Paths are separated by dots and
+// values that do not exist are nil.
+type Change struct {
+	Path     string `json:"path"`
+	Stored   any    `json:"stored"`
+	Expected any    `json:"expected"`
+}
+
+// Consistent returns whether the store did not differ from the data source.
+func (r *Report) Consistent() bool {
+	return len(r.Missing) == 0 && len(r.Stale) == 0 && len(r.Orphaned) == 0
+}
+
+// DryRun compares the store with the data source like a reconciliation does, but only reports the differences
+// instead of resolving them. Orphaned entries are reported regardless of whether they would be removed.
+func (r *Reconciliation) DryRun(ctx context.Context, store Store) (*Report, error) {
+	resources, stored, err := r.list(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	missingItems, staleItems := r.generateDiff(resources, stored)
+	report := &Report{
+		Dataset:  r.resource.GetGroupVersionName(),
+		Missing:  make([]string, 0, len(missingItems)),
+		Stale:    make([]string, 0, len(staleItems)),
+		Orphaned: findOrphans(resources, r.managed(stored)),
+		Samples:  make([]Sample, 0, min(len(staleItems), maxReportSamples)),
+	}
+
+	for _, item := range missingItems {
+		report.Missing = append(report.Missing, item.GetName())
+	}
+	slices.Sort(report.Missing)
+
+	slices.SortFunc(staleItems, func(a, b *unstructured.Unstructured) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	for _, item := range staleItems {
+		report.Stale = append(report.Stale, item.GetName())
+		if len(report.Samples) < maxReportSamples {
+			report.Samples = append(report.Samples, Sample{
+				Key:     item.GetName(),
+				Changes: diffFields("", stored[item.GetName()].Object, item.Object),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// diffFields returns the changes between the stored and the expected object sorted by path.
+// Nested objects are compared field by field, all other values as a whole. The "_id" of store entries is ignored.
+func diffFields(path string, stored map[string]any, expected map[string]any) []Change {
+	keys := slices.Sorted(maps.Keys(stored))
+	for key := range expected {
+		if _, ok := stored[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	changes := make([]Change, 0)
+	for _, key := range keys {
+		if path == "" && key == "_id" {
+			continue
+		}
+
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		storedValue, expectedValue := stored[key], expected[key]
+		storedObject, storedIsObject := storedValue.(map[string]any)
+		expectedObject, expectedIsObject := expectedValue.(map[string]any)
+		if storedIsObject && expectedIsObject {
+			changes = append(changes, diffFields(fieldPath, storedObject, expectedObject)...)
+			continue
+		}
+
+		if !reflect.DeepEqual(storedValue, expectedValue) {
+			changes = append(changes, Change{Path: fieldPath, Stored: storedValue, Expected: expectedValue})
+		}
+	}
+	return changes
+}
@@ -5,6 +5,8 @@
 package reconciliation
 
 import (
+	"context"
+
 	"github.com/telekom/quasar/internal/config"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -24,10 +26,15 @@ func NewDataSourceFromStore(store Store, resource config.Resource) *StoreDataSou
 }
 
 // ListResources retrieves all resources from the store relevant for reconciliation
-func (s *StoreDataSource) ListResources() ([]unstructured.Unstructured, error) {
-	resources, err := s.store.List(s.resource.GetGroupVersionName(), "", 0)
+func (s *StoreDataSource) ListResources(ctx context.Context) ([]unstructured.Unstructured, error) {
+	resources, err := s.store.List(ctx, s.resource.GetGroupVersionName(), "", 0)
 	if err != nil {
 		return nil, err
 	}
 	return resources, nil
 }
+
+// Store returns the store the resources are listed from.
+func (s *StoreDataSource) Store() Store {
+	return s.store
+}
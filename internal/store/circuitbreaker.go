@@ -0,0 +1,314 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitHalfOpen
+	CircuitOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitOpenError is returned by a CircuitBreakerStore instead of calling the store while the circuit is open.
+type CircuitOpenError struct {
+	StoreType  string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker of store %q is open, retry after %s", e.StoreType, e.RetryAfter)
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerStore wraps a store and stops calling it after too many consecutive failures or timeouts.
+// While the circuit is open, calls fail immediately with a CircuitOpenError. Once the open duration has passed,
+// the circuit becomes half-open and lets a limited number of probes through. A successful probe closes the circuit,
+// a failed probe opens it again.
+type CircuitBreakerStore struct {
+	Store
+	storeType string
+	config    config.CircuitBreaker
+	logger    zerolog.Logger
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openUntil time.Time
+	probes    int
+
+	stateGauge prometheus.Gauge
+	rejected   prometheus.Counter
+	timeouts   prometheus.Counter
+}
+
+// newCircuitBreakerStore guards the store with a circuit breaker. The timeout of the breaker is raised to the timeout
+// of the store, so that the breaker does not cancel operations the store would still allow to complete.
+func newCircuitBreakerStore(
+	managerId string,
+	storeType string,
+	store Store,
+	breakerConfig *config.CircuitBreaker,
+	storeTimeout time.Duration,
+) *CircuitBreakerStore {
+	breaker := &CircuitBreakerStore{
+		Store:     store,
+		storeType: storeType,
+		config:    *breakerConfig,
+		logger: log.With().
+			Str("component", "CircuitBreaker").
+			Str("id", managerId).
+			Str("storeType", storeType).
+			Logger(),
+		stateGauge: metrics.GetOrCreateCustom(storeType+"_circuit_breaker_state", "manager").WithLabelValues(managerId),
+		rejected:   metrics.GetOrCreateCustomCounter(storeType+"_circuit_breaker_rejected_total", "manager").WithLabelValues(managerId),
+		timeouts:   metrics.GetOrCreateCustomCounter(storeType+"_circuit_breaker_timeouts_total", "manager").WithLabelValues(managerId),
+	}
+	if breaker.config.Timeout > 0 && storeTimeout > breaker.config.Timeout {
+		breaker.config.Timeout = storeTimeout
+	}
+	breaker.stateGauge.Set(float64(CircuitClosed))
+	return breaker
+}
+
+func (c *CircuitBreakerStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	_, err := call(ctx, c, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.Store.Create(ctx, obj)
+	})
+	return err
+}
+
+func (c *CircuitBreakerStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	_, err := call(ctx, c, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.Store.Update(ctx, oldObj, newObj)
+	})
+	return err
+}
+
+func (c *CircuitBreakerStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	_, err := call(ctx, c, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.Store.Delete(ctx, obj)
+	})
+	return err
+}
+
+func (c *CircuitBreakerStore) Count(ctx context.Context, dataset string) (int, error) {
+	return call(ctx, c, func(ctx context.Context) (int, error) {
+		return c.Store.Count(ctx, dataset)
+	})
+}
+
+func (c *CircuitBreakerStore) Keys(ctx context.Context, dataset string) ([]string, error) {
+	return call(ctx, c, func(ctx context.Context) ([]string, error) {
+		return c.Store.Keys(ctx, dataset)
+	})
+}
+
+func (c *CircuitBreakerStore) Read(ctx context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	return call(ctx, c, func(ctx context.Context) (*unstructured.Unstructured, error) {
+		return c.Store.Read(ctx, dataset, key)
+	})
+}
+
+func (c *CircuitBreakerStore) List(ctx context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	return call(ctx, c, func(ctx context.Context) ([]unstructured.Unstructured, error) {
+		return c.Store.List(ctx, dataset, fieldSelector, limit)
+	})
+}
+
+// Connected returns false while the circuit is open, so that reads fail over to replicas.
+func (c *CircuitBreakerStore) Connected() bool {
+	return c.State() != CircuitOpen && c.Store.Connected()
+}
+
+// WriteOnly passes through whether the wrapped store is write-only.
+func (c *CircuitBreakerStore) WriteOnly() bool {
+	return isWriteOnly(c.Store)
+}
+
+// Unwrap returns the wrapped store.
+func (c *CircuitBreakerStore) Unwrap() Store {
+	return c.Store
+}
+
+// State returns the current state of the circuit.
+func (c *CircuitBreakerStore) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CircuitOpen && !time.Now().Before(c.openUntil) {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// call runs the operation if the circuit of the breaker allows it and records its outcome.
+func call[T any](ctx context.Context, c *CircuitBreakerStore, operation func(context.Context) (T, error)) (T, error) {
+	probe, err := c.acquire()
+	if err != nil {
+		c.rejected.Inc()
+		var zero T
+		return zero, err
+	}
+
+	value, err := run(ctx, c, operation)
+	// calls abandoned by their caller do not tell anything about the health of the store
+	abandoned := err != nil && ctx.Err() != nil && !errors.Is(err, ErrStoreTimeout)
+	c.release(probe, err, abandoned)
+	return value, err
+}
+
+type callResult[T any] struct {
+	value T
+	err   error
+}
+
+// run runs the operation with a context that is cancelled once the timeout of the breaker has passed and stops
+// waiting for it when the context is done. Operations of stores that do not honor the context keep running in the
+// background, but since timeouts count as failures, the circuit opens before too many of them pile up.
+// Only the timeouts of the breaker and of the managed store are reported as ErrStoreTimeout, deadlines of the
+// caller are returned as they are.
+func run[T any](ctx context.Context, c *CircuitBreakerStore, operation func(context.Context) (T, error)) (T, error) {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, c.config.Timeout, errOperationTimeout)
+		defer cancel()
+	}
+
+	results := make(chan callResult[T], 1)
+	go func() {
+		value, err := operation(ctx)
+		results <- callResult[T]{value, err}
+	}()
+
+	var result callResult[T]
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		result.err = ctx.Err()
+	}
+
+	if result.err != nil && errors.Is(context.Cause(ctx), errOperationTimeout) {
+		c.timeouts.Inc()
+		var zero T
+		return zero, fmt.Errorf("%w: store %q did not respond in time", ErrStoreTimeout, c.storeType)
+	}
+	return result.value, result.err
+}
+
+// acquire returns whether the call is allowed and whether it probes a half-open circuit.
+func (c *CircuitBreakerStore) acquire() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case CircuitOpen:
+		if remaining := time.Until(c.openUntil); remaining > 0 {
+			return false, &CircuitOpenError{StoreType: c.storeType, RetryAfter: remaining}
+		}
+		c.setState(CircuitHalfOpen)
+		fallthrough
+
+	case CircuitHalfOpen:
+		if c.probes >= max(c.config.HalfOpenProbes, 1) {
+			return false, &CircuitOpenError{StoreType: c.storeType}
+		}
+		c.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+// release records the outcome of a call. Abandoned calls neither count as failure nor as success.
+func (c *CircuitBreakerStore) release(probe bool, err error, abandoned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if probe {
+		c.probes--
+	}
+	if abandoned {
+		return
+	}
+
+	if !isFailure(err) {
+		c.failures = 0
+		if probe && c.state == CircuitHalfOpen {
+			c.setState(CircuitClosed)
+		}
+		return
+	}
+
+	c.failures++
+	if (probe && c.state == CircuitHalfOpen) || c.failures >= max(c.config.FailureThreshold, 1) {
+		c.openUntil = time.Now().Add(c.config.OpenDuration)
+		c.setState(CircuitOpen)
+	}
+}
+
+// setState changes the state of the circuit. The caller must hold the lock.
+func (c *CircuitBreakerStore) setState(state CircuitState) {
+	if c.state == state {
+		return
+	}
+
+	event := c.logger.Info()
+	if state == CircuitOpen {
+		event = c.logger.Warn().Int("failures", c.failures).Dur("openDuration", c.config.OpenDuration)
+	}
+	event.Str("from", c.state.String()).Str("to", state.String()).Msg("Circuit breaker changed state")
+
+	c.state = state
+	c.stateGauge.Set(float64(state))
+}
+
+// unwrapStore returns the store wrapped by a circuit breaker or the store itself if it is not wrapped.
+func unwrapStore(store Store) Store {
+	if breaker, ok := store.(*CircuitBreakerStore); ok {
+		return breaker.Unwrap()
+	}
+	return store
+}
+
+// isFailure returns whether the error indicates that the store is unhealthy.
+// Resources that do not exist, reads from write-only stores and calls cancelled by the caller are expected and
+// do not count as failures.
+func isFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, ErrResourceNotFound) &&
+		!errors.Is(err, ErrWriteOnlyStore) &&
+		!errors.Is(err, context.Canceled)
+}
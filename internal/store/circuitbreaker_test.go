@@ -0,0 +1,173 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/test"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// blockingStore is a memory store whose writes block until it is released.
+type blockingStore struct {
+	*MemoryStore
+	release chan struct{}
+}
+
+func (b *blockingStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	<-b.release
+	return b.MemoryStore.Create(ctx, obj)
+}
+
+func createCircuitBreakerConfig() config.CircuitBreaker {
+	return config.CircuitBreaker{
+		Enabled:          true,
+		FailureThreshold: 2,
+		Timeout:          50 * time.Millisecond,
+		OpenDuration:     100 * time.Millisecond,
+		HalfOpenProbes:   1,
+	}
+}
+
+func TestCircuitBreakerStore_OpenAndRecover(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	flaky := newFlakyStore(3)
+	breakerConfig := createCircuitBreakerConfig()
+	breaker := newCircuitBreakerStore("test-circuit-breaker", "flaky", flaky, &breakerConfig, 0)
+
+	assertions.ErrorIs(breaker.Create(context.Background(), subscriptions[0]), errFlakyStore)
+	assertions.Equal(CircuitClosed, breaker.State(), "circuit should stay closed below the failure threshold")
+	assertions.ErrorIs(breaker.Create(context.Background(), subscriptions[0]), errFlakyStore)
+	assertions.Equal(CircuitOpen, breaker.State(), "circuit should open once the failure threshold is reached")
+	assertions.False(breaker.Connected())
+
+	err := breaker.Create(context.Background(), subscriptions[0])
+	assertions.ErrorIs(err, ErrCircuitOpen, "calls should be short-circuited while the circuit is open")
+	var openErr *CircuitOpenError
+	if assertions.ErrorAs(err, &openErr) {
+		assertions.Equal("flaky", openErr.StoreType)
+		assertions.Positive(openErr.RetryAfter)
+	}
+	assertions.Empty(flaky.Applied())
+
+	time.Sleep(breakerConfig.OpenDuration)
+	assertions.Equal(CircuitHalfOpen, breaker.State())
+	assertions.ErrorIs(breaker.Create(context.Background(), subscriptions[0]), errFlakyStore)
+	assertions.Equal(CircuitOpen, breaker.State(), "failed probe should open the circuit again")
+
+	time.Sleep(breakerConfig.OpenDuration)
+	assertions.NoError(breaker.Create(context.Background(), subscriptions[0]))
+	assertions.Equal(CircuitClosed, breaker.State(), "successful probe should close the circuit")
+	assertions.True(breaker.Connected())
+
+	_, err = breaker.Read(context.Background(), "unknown", "unknown")
+	assertions.ErrorIs(err, ErrResourceNotFound)
+	assertions.Equal(CircuitClosed, breaker.State(), "missing resources should not count as failures")
+}
+
+func TestCircuitBreakerStore_Timeout(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	blocking := &blockingStore{MemoryStore: new(MemoryStore), release: make(chan struct{})}
+	blocking.Initialize()
+	defer close(blocking.release)
+
+	breakerConfig := createCircuitBreakerConfig()
+	breaker := newCircuitBreakerStore("test-circuit-breaker", "blocking", blocking, &breakerConfig, 0)
+
+	assertions.ErrorIs(breaker.Create(context.Background(), subscriptions[0]), ErrStoreTimeout)
+	assertions.ErrorIs(breaker.Create(context.Background(), subscriptions[0]), ErrStoreTimeout)
+	assertions.Equal(CircuitOpen, breaker.State(), "timeouts should count as failures")
+
+	start := time.Now()
+	assertions.ErrorIs(breaker.Create(context.Background(), subscriptions[0]), ErrCircuitOpen)
+	assertions.Less(time.Since(start), breakerConfig.Timeout, "open circuit should not wait for the store")
+}
+
+func TestCircuitBreakerStore_StoreTimeout(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	blocking := &blockingStore{MemoryStore: new(MemoryStore), release: make(chan struct{})}
+	blocking.Initialize()
+
+	breakerConfig := createCircuitBreakerConfig()
+	storeTimeout := 4 * breakerConfig.Timeout
+	breaker := newCircuitBreakerStore("test-circuit-breaker", "slow", blocking, &breakerConfig, storeTimeout)
+
+	go func() {
+		time.Sleep(2 * breakerConfig.Timeout)
+		close(blocking.release)
+	}()
+
+	start := time.Now()
+	assertions.NoError(breaker.Create(context.Background(), subscriptions[0]), "breaker should wait as long as the store")
+	assertions.GreaterOrEqual(time.Since(start), 2*breakerConfig.Timeout)
+	assertions.Equal(CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreakerStore_ManagerMetrics(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	breakerConfig := createCircuitBreakerConfig()
+	failing := newCircuitBreakerStore("failing-manager", "shared", newFlakyStore(10), &breakerConfig, 0)
+	healthy := newCircuitBreakerStore("healthy-manager", "shared", newFlakyStore(0), &breakerConfig, 0)
+
+	for range breakerConfig.FailureThreshold {
+		assertions.ErrorIs(failing.Create(context.Background(), subscriptions[0]), errFlakyStore)
+	}
+	assertions.NoError(healthy.Create(context.Background(), subscriptions[0]))
+
+	state := metrics.GetOrCreateCustom("shared_circuit_breaker_state", "manager")
+	assertions.Equal(float64(CircuitOpen), testutil.ToFloat64(state.WithLabelValues("failing-manager")))
+	assertions.Equal(float64(CircuitClosed), testutil.ToFloat64(state.WithLabelValues("healthy-manager")),
+		"breakers of different managers should not share their metrics")
+}
+
+func TestCircuitBreakerStore_CallerDeadline(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	blocking := &blockingStore{MemoryStore: new(MemoryStore), release: make(chan struct{})}
+	blocking.Initialize()
+	defer close(blocking.release)
+
+	breakerConfig := createCircuitBreakerConfig()
+	breakerConfig.Timeout = time.Second
+	breaker := newCircuitBreakerStore("test-circuit-breaker", "deadline", blocking, &breakerConfig, 0)
+
+	for range breakerConfig.FailureThreshold {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		err := breaker.Create(ctx, subscriptions[0])
+		cancel()
+		assertions.ErrorIs(err, context.DeadlineExceeded)
+		assertions.NotErrorIs(err, ErrStoreTimeout, "deadlines of the caller should not be reported as store timeouts")
+	}
+	assertions.Equal(CircuitClosed, breaker.State(), "deadlines of the caller should not count as failures")
+
+	managed := &ManagedStore{Store: breaker, Type: "deadline", Timeout: 10 * time.Millisecond}
+	for range breakerConfig.FailureThreshold {
+		assertions.ErrorIs(managed.Create(context.Background(), subscriptions[0]), ErrStoreTimeout)
+	}
+	assertions.Equal(CircuitOpen, breaker.State(), "operation timeouts of the store should count as failures")
+}
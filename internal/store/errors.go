@@ -9,4 +9,31 @@ import "errors"
 var (
 	ErrUnknownStoreType = errors.New("unknown store type")
 	ErrResourceNotFound = errors.New("resource not found")
+
+	ErrUnknownRedisMode              = errors.New("unknown redis mode")
+	ErrIncompleteRedisSentinelConfig = errors.New("redis sentinel mode requires a master name and at least one sentinel address")
+	ErrIncompleteRedisClusterConfig  = errors.New("redis cluster mode requires at least one seed address")
+
+	ErrEmptyPostgresIndex = errors.New("postgres index requires at least one field")
+
+	ErrWriteOnlyStore   = errors.New("store is write-only and cannot be read from")
+	ErrWriteOnlyPrimary = errors.New("a write-only store cannot be used as authoritative store")
+	ErrWriteOnlyReplica = errors.New("a write-only store can only be used as sink")
+
+	ErrUnknownStoreRole            = errors.New("unknown store role")
+	ErrUnknownStoreWritePolicy     = errors.New("unknown store write policy")
+	ErrMissingAuthoritativeStore   = errors.New("exactly one authoritative store must be configured")
+	ErrMultipleAuthoritativeStores = errors.New("only one authoritative store can be configured")
+	ErrAsyncAuthoritativeStore     = errors.New("the authoritative store must be written synchronously")
+	ErrUnknownWriteConsistency     = errors.New("unknown write consistency")
+
+	ErrWebhookDeliveryFailed = errors.New("webhook delivery failed")
+
+	ErrInvalidRetryQueueConfig = errors.New("invalid retry queue configuration")
+
+	ErrCircuitOpen  = errors.New("circuit breaker is open")
+	ErrStoreTimeout = errors.New("store operation timed out")
+
+	// errOperationTimeout is the cause of contexts cancelled by the operation timeout of a store
+	errOperationTimeout = errors.New("operation timeout of the store exceeded")
 )
@@ -0,0 +1,467 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/utils"
+	bolt "go.etcd.io/bbolt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	fileItemsBucket   = []byte("items")
+	fileIndexesBucket = []byte("indexes")
+
+	// errStopIteration is returned by iteration callbacks to stop early and never leaves the store.
+	errStopIteration = errors.New("stop iteration")
+)
+
+// FileStore persists every dataset in an embedded bbolt database. Each dataset is a top-level bucket holding
+// the resources (keyed like the MongoStore) and one index bucket per indexed field path.
+type FileStore struct {
+	db        *bolt.DB
+	indexes   sync.Map
+	connected atomic.Bool
+}
+
+func (f *FileStore) Initialize() {
+	var err error
+	path := config.Current.Store.File.Path
+
+	f.db, err = bolt.Open(path, 0o600, &bolt.Options{Timeout: config.Current.Store.File.OpenTimeout})
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("Could not open file-store")
+		return
+	}
+
+	f.connected.Store(true)
+	log.Info().Str("path", path).Msg("File-store opened")
+}
+
+func (f *FileStore) InitializeResource(dataSource reconciliation.DataSource, resourceConfig *config.Resource) {
+	_ = dataSource
+	dataset := resourceConfig.GetGroupVersionName()
+
+	paths := make([]string, 0)
+	for _, index := range resourceConfig.MongoIndexes {
+		for field := range index {
+			if !slices.Contains(paths, field) {
+				paths = append(paths, field)
+			}
+		}
+	}
+	f.indexes.Store(dataset, paths)
+
+	err := f.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(dataset))
+		if err != nil {
+			return err
+		}
+
+		if _, err := bucket.CreateBucketIfNotExists(fileItemsBucket); err != nil {
+			return err
+		}
+
+		indexes, err := bucket.CreateBucketIfNotExists(fileIndexesBucket)
+		if err != nil {
+			return err
+		}
+
+		return f.rebuildIndexes(bucket, indexes, paths)
+	})
+	if err != nil {
+		resource := resourceConfig.GetGroupVersionResource()
+		log.Warn().Fields(utils.CreateFieldForResource(&resource)).Err(err).Msg("Could not create indexes in file-store")
+	}
+
+	go f.collectMetrics(dataset)
+}
+
+func (f *FileStore) Create(_ context.Context, obj *unstructured.Unstructured) error {
+	collectionName := utils.GetGroupVersionId(obj)
+
+	if err := f.put(obj, obj); err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(collectionName, "create", obj)).
+			Msg("Failed to create or update document in file-store")
+		return err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(collectionName, "create", obj)).
+		Msg("Resource created or updated in file-store")
+	return nil
+}
+
+func (f *FileStore) Update(_ context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	collectionName := utils.GetGroupVersionId(oldObj)
+
+	if err := f.put(oldObj, newObj); err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(collectionName, "update", oldObj)).
+			Msg("Failed to update document in file-store")
+		return err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(collectionName, "update", oldObj)).
+		Msg("Resource updated in file-store")
+	return nil
+}
+
+func (f *FileStore) Delete(_ context.Context, obj *unstructured.Unstructured) error {
+	collectionName := utils.GetGroupVersionId(obj)
+
+	id, err := utils.GetMongoId(obj)
+	if err == nil {
+		err = f.db.Update(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(collectionName))
+			if bucket == nil {
+				return nil
+			}
+			return f.remove(bucket, collectionName, []byte(id))
+		})
+	}
+
+	if err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(collectionName, "delete", obj)).
+			Msg("Failed to delete document in file-store")
+		return err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(collectionName, "delete", obj)).
+		Msg("Resource deleted in file-store")
+	return nil
+}
+
+func (f *FileStore) Count(_ context.Context, collectionName string) (int, error) {
+	count := 0
+	err := f.db.View(func(tx *bolt.Tx) error {
+		if items := f.items(tx, collectionName); items != nil {
+			count = items.Stats().KeyN
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (f *FileStore) Keys(_ context.Context, collectionName string) ([]string, error) {
+	keys := make([]string, 0)
+	err := f.db.View(func(tx *bolt.Tx) error {
+		items := f.items(tx, collectionName)
+		if items == nil {
+			return nil
+		}
+
+		return items.ForEach(func(key, _ []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (f *FileStore) Read(_ context.Context, collectionName string, key string) (*unstructured.Unstructured, error) {
+	var result unstructured.Unstructured
+	err := f.db.View(func(tx *bolt.Tx) error {
+		items := f.items(tx, collectionName)
+		if items == nil {
+			return ErrResourceNotFound
+		}
+
+		data := items.Get([]byte(key))
+		if data == nil {
+			return ErrResourceNotFound
+		}
+		return result.UnmarshalJSON(data)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrResourceNotFound) {
+			log.Error().Err(err).
+				Fields(utils.CreateFieldsForCollection(collectionName, "read", nil)).
+				Str("key", key).
+				Msg("Failed to read resource from file-store")
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (f *FileStore) List(_ context.Context, collectionName string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	fields := utils.ParseFieldSelector(fieldSelector)
+
+	var results []unstructured.Unstructured
+	err := f.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(collectionName))
+		if bucket == nil {
+			return nil
+		}
+		items := bucket.Bucket(fileItemsBucket)
+		if items == nil {
+			return nil
+		}
+
+		return f.forEachCandidate(bucket, fields, func(key []byte) error {
+			data := items.Get(key)
+			if data == nil {
+				return nil
+			}
+
+			var resource unstructured.Unstructured
+			if err := resource.UnmarshalJSON(data); err != nil {
+				log.Error().Err(err).
+					Fields(utils.CreateFieldsForCollectionWithListOptions(collectionName, "list", nil, limit, fieldSelector)).
+					Msg("Failed to decode resource from file-store")
+				return nil
+			}
+
+			if !utils.MatchFields(&resource, fields) {
+				return nil
+			}
+
+			results = append(results, resource)
+			if limit > 0 && int64(len(results)) >= limit {
+				return errStopIteration
+			}
+			return nil
+		})
+	})
+	if err != nil && !errors.Is(err, errStopIteration) {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollectionWithListOptions(collectionName, "list", nil, limit, fieldSelector)).
+			Msg("Failed to list resources from file-store")
+		return nil, err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollectionWithListOptions(collectionName, "list", nil, limit, fieldSelector)).
+		Int("count", len(results)).
+		Msg("Resources listed from file-store")
+
+	return results, nil
+}
+
+func (f *FileStore) Shutdown() {
+	if f.Connected() {
+		if err := f.db.Close(); err != nil {
+			log.Error().Err(err).Msg("Could not close file-store")
+		}
+	}
+	f.connected.Store(false)
+}
+
+func (f *FileStore) Connected() bool {
+	return f.connected.Load()
+}
+
+func (f *FileStore) put(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	collectionName := utils.GetGroupVersionId(oldObj)
+
+	oldId, err := utils.GetMongoId(oldObj)
+	if err != nil {
+		return err
+	}
+
+	newId, err := utils.GetMongoId(newObj)
+	if err != nil {
+		return err
+	}
+
+	data, err := newObj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return f.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(collectionName))
+		if err != nil {
+			return err
+		}
+
+		items, err := bucket.CreateBucketIfNotExists(fileItemsBucket)
+		if err != nil {
+			return err
+		}
+
+		if _, err := bucket.CreateBucketIfNotExists(fileIndexesBucket); err != nil {
+			return err
+		}
+
+		if err := f.remove(bucket, collectionName, []byte(oldId)); err != nil {
+			return err
+		}
+
+		if err := items.Put([]byte(newId), data); err != nil {
+			return err
+		}
+		return f.index(bucket, f.getIndexPaths(collectionName), []byte(newId), newObj)
+	})
+}
+
+// remove deletes the resource with the given key and its index entries from the dataset bucket.
+func (f *FileStore) remove(bucket *bolt.Bucket, collectionName string, key []byte) error {
+	items := bucket.Bucket(fileItemsBucket)
+	if items == nil {
+		return nil
+	}
+
+	data := items.Get(key)
+	if data == nil {
+		return nil
+	}
+
+	var previous unstructured.Unstructured
+	if err := previous.UnmarshalJSON(data); err == nil {
+		indexes := bucket.Bucket(fileIndexesBucket)
+		for _, path := range f.getIndexPaths(collectionName) {
+			index := indexes.Bucket([]byte(path))
+			value, ok := indexValue(&previous, path)
+			if index == nil || !ok {
+				continue
+			}
+
+			if err := index.Delete(fileIndexKey(value, key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return items.Delete(key)
+}
+
+func (f *FileStore) index(bucket *bolt.Bucket, paths []string, key []byte, obj *unstructured.Unstructured) error {
+	indexes := bucket.Bucket(fileIndexesBucket)
+	for _, path := range paths {
+		index, err := indexes.CreateBucketIfNotExists([]byte(path))
+		if err != nil {
+			return err
+		}
+
+		if value, ok := indexValue(obj, path); ok {
+			if err := index.Put(fileIndexKey(value, key), nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FileStore) rebuildIndexes(bucket *bolt.Bucket, indexes *bolt.Bucket, paths []string) error {
+	for _, path := range paths {
+		if indexes.Bucket([]byte(path)) != nil {
+			continue
+		}
+
+		if _, err := indexes.CreateBucket([]byte(path)); err != nil {
+			return err
+		}
+
+		err := bucket.Bucket(fileItemsBucket).ForEach(func(key, data []byte) error {
+			var obj unstructured.Unstructured
+			if err := obj.UnmarshalJSON(data); err != nil {
+				return nil
+			}
+			return f.index(bucket, []string{path}, key, &obj)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachCandidate calls fn for the keys that may match the given fields, using the first indexed field if possible.
+func (f *FileStore) forEachCandidate(bucket *bolt.Bucket, fields map[string]string, fn func(key []byte) error) error {
+	if indexes := bucket.Bucket(fileIndexesBucket); indexes != nil {
+		for path, value := range fields {
+			index := indexes.Bucket([]byte(path))
+			if index == nil {
+				continue
+			}
+
+			prefix := fileIndexKey(value, nil)
+			cursor := index.Cursor()
+			for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+				if err := fn(k[len(prefix):]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return bucket.Bucket(fileItemsBucket).ForEach(func(key, _ []byte) error {
+		return fn(key)
+	})
+}
+
+func (f *FileStore) items(tx *bolt.Tx, collectionName string) *bolt.Bucket {
+	bucket := tx.Bucket([]byte(collectionName))
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Bucket(fileItemsBucket)
+}
+
+func (f *FileStore) getIndexPaths(collectionName string) []string {
+	paths, ok := f.indexes.Load(collectionName)
+	if !ok {
+		return nil
+	}
+	return paths.([]string)
+}
+
+func (f *FileStore) collectMetrics(resourceName string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error().Msgf("Recovered from %v during file-store metric collection", err)
+		}
+	}()
+
+	for f.Connected() {
+		count, err := f.Count(context.Background(), resourceName)
+		if err != nil {
+			log.Error().Err(err).Fields(map[string]any{
+				"collection": resourceName,
+			}).Msg("Could not count documents in file-store")
+
+			time.Sleep(15 * time.Second)
+			continue
+		}
+
+		metrics.GetOrCreateCustom(resourceName + "_file_count").WithLabelValues().Set(float64(count))
+		time.Sleep(15 * time.Second)
+	}
+}
+
+// fileIndexKey builds the key of an index entry, which is the indexed value followed by a separator and the resource key.
+func fileIndexKey(value string, key []byte) []byte {
+	return fmt.Appendf(nil, "%s\x00%s", strings.ReplaceAll(value, "\x00", ""), key)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+)
+
+func createFileStore(t *testing.T, path string) *FileStore {
+	t.Helper()
+
+	previousFileConfig := config.Current.Store.File
+	config.Current.Store.File.Path = path
+	t.Cleanup(func() {
+		config.Current.Store.File = previousFileConfig
+	})
+
+	fileStore := new(FileStore)
+	fileStore.Initialize()
+
+	resourceConfig := config.Resource{}
+	resourceConfig.Kubernetes.Group = "subscriber.horizon.telekom.de"
+	resourceConfig.Kubernetes.Version = "v1"
+	resourceConfig.Kubernetes.Resource = "subscriptions"
+	resourceConfig.MongoIndexes = []config.MongoResourceIndex{
+		{"spec.subscription.subscriptionId": 1},
+	}
+	fileStore.InitializeResource(nil, &resourceConfig)
+
+	return fileStore
+}
+
+func TestFileStore_CRUD(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	fileStore := createFileStore(t, filepath.Join(t.TempDir(), "quasar.db"))
+	defer fileStore.Shutdown()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	for _, subscription := range subscriptions {
+		assertions.NoError(fileStore.Create(context.Background(), subscription))
+	}
+
+	count, err := fileStore.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count)
+
+	keys, err := fileStore.Keys(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.ElementsMatch([]string{string(subscriptions[0].GetUID()), string(subscriptions[1].GetUID())}, keys)
+
+	updated := subscriptions[0].DeepCopy()
+	updated.SetLabels(map[string]string{"file_test": "true"})
+	assertions.NoError(fileStore.Update(context.Background(), subscriptions[0], updated))
+
+	obj, err := fileStore.Read(context.Background(), dataset, string(subscriptions[0].GetUID()))
+	assertions.NoError(err)
+	assertions.Equal("true", obj.GetLabels()["file_test"])
+
+	assertions.NoError(fileStore.Delete(context.Background(), subscriptions[0]))
+	_, err = fileStore.Read(context.Background(), dataset, string(subscriptions[0].GetUID()))
+	assertions.ErrorIs(err, ErrResourceNotFound)
+
+	count, _ = fileStore.Count(context.Background(), dataset)
+	assertions.Equal(len(subscriptions)-1, count)
+}
+
+func TestFileStore_List(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	fileStore := createFileStore(t, filepath.Join(t.TempDir(), "quasar.db"))
+	defer fileStore.Shutdown()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+	for _, subscription := range subscriptions {
+		assertions.NoError(fileStore.Create(context.Background(), subscription))
+	}
+
+	items, err := fileStore.List(context.Background(), dataset, "", 0)
+	assertions.NoError(err)
+	assertions.Len(items, len(subscriptions))
+
+	items, err = fileStore.List(context.Background(), dataset, "", 1)
+	assertions.NoError(err)
+	assertions.Len(items, 1, "limit should be respected")
+
+	subscriptionId := subscriptions[1].GetName()
+	items, err = fileStore.List(context.Background(), dataset, "spec.subscription.subscriptionId="+subscriptionId, 0)
+	assertions.NoError(err)
+	if assertions.Len(items, 1, "indexed field selector should match exactly one item") {
+		assertions.Equal(subscriptionId, items[0].GetName())
+	}
+
+	items, err = fileStore.List(context.Background(), dataset, "metadata.name="+subscriptionId, 0)
+	assertions.NoError(err)
+	assertions.Len(items, 1, "unindexed field selector should match exactly one item")
+
+	assertions.NoError(fileStore.Delete(context.Background(), subscriptions[1]))
+	items, err = fileStore.List(context.Background(), dataset, "spec.subscription.subscriptionId="+subscriptionId, 0)
+	assertions.NoError(err)
+	assertions.Empty(items, "index entries should be removed on delete")
+}
+
+func TestFileStore_Persistence(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	path := filepath.Join(t.TempDir(), "quasar.db")
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	fileStore := createFileStore(t, path)
+	for _, subscription := range subscriptions {
+		assertions.NoError(fileStore.Create(context.Background(), subscription))
+	}
+	fileStore.Shutdown()
+	assertions.False(fileStore.Connected())
+
+	reopenedStore := createFileStore(t, path)
+	defer reopenedStore.Shutdown()
+
+	count, err := reopenedStore.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count, "resources should survive a restart")
+}
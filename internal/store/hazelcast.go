@@ -8,7 +8,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,10 +25,10 @@ import (
 )
 
 type HazelcastStore struct {
-	client          *hazelcast.Client
-	ctx             context.Context
-	reconciliations sync.Map
-	connected       atomic.Bool
+	reconnectCallbacks
+	client    *hazelcast.Client
+	ctx       context.Context
+	connected atomic.Bool
 }
 
 func (s *HazelcastStore) Initialize() {
@@ -85,9 +84,19 @@ func (s *HazelcastStore) Initialize() {
 	if err != nil {
 		log.Error().Err(err).Msg("Could not create hazelcast client lifecycle listener!")
 	}
+
+	// entries of a removed member may be lost if they were not backed up in time
+	_, err = s.client.AddMembershipListener(func(event cluster.MembershipStateChanged) {
+		if event.State == cluster.MembershipStateRemoved {
+			s.notifyReconnect()
+		}
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Could not register membership listener for reconciliation")
+	}
 }
 
-func (s *HazelcastStore) InitializeResource(dataSource reconciler.DataSource, resourceConfig *config.Resource) {
+func (s *HazelcastStore) InitializeResource(_ reconciler.DataSource, resourceConfig *config.Resource) {
 	mapName := resourceConfig.GetGroupVersionName()
 	cacheMap, err := s.client.GetMap(s.ctx, mapName)
 	if err != nil {
@@ -105,38 +114,10 @@ func (s *HazelcastStore) InitializeResource(dataSource reconciler.DataSource, re
 		}
 	}
 
-	interval := config.Current.Store.Hazelcast.ReconciliationInterval
-	if interval < 60*time.Second {
-		log.Warn().Msg("Reconciliation interval is set to less than 60 seconds. Setting it to 60 seconds.")
-		interval = 60 * time.Second
-	}
-
-	recon := reconciler.NewReconciliation(dataSource, resourceConfig)
-	s.reconciliations.Store(mapName, recon)
-
-	// start reconcile immediately for provisioning mode to ensure initial store filling
-	if (config.Current.Mode == config.ModeProvisioning) && s.Connected() {
-		recon.SafeReconcile(s)
-	}
-
-	// start reconcile periodically for all modes
-	go recon.StartPeriodicReconcile(s.ctx, interval, s)
-
-	_, err = s.client.AddMembershipListener(func(event cluster.MembershipStateChanged) {
-		if event.State == cluster.MembershipStateRemoved {
-			recon.SafeReconcile(s)
-		}
-	})
-	if err != nil {
-		log.Error().Err(err).Fields(map[string]any{
-			"cache": resourceConfig.GetGroupVersionName(),
-		}).Msg("Could not register membership listener for reconciliation")
-	}
-
 	go s.collectMetrics(resourceConfig.GetGroupVersionName())
 }
 
-func (s *HazelcastStore) Create(obj *unstructured.Unstructured) error {
+func (s *HazelcastStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
 	cacheMap := s.getMap(obj)
 
 	json, err := obj.MarshalJSON()
@@ -145,7 +126,7 @@ func (s *HazelcastStore) Create(obj *unstructured.Unstructured) error {
 		return err
 	}
 
-	if err := cacheMap.Set(s.ctx, obj.GetName(), serialization.JSON(json)); err != nil {
+	if err := cacheMap.Set(ctx, obj.GetName(), serialization.JSON(json)); err != nil {
 		log.Error().
 			Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "create", obj)).
 			Err(err).
@@ -159,7 +140,7 @@ func (s *HazelcastStore) Create(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (s *HazelcastStore) Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+func (s *HazelcastStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
 	cacheMap := s.getMap(oldObj)
 
 	json, err := newObj.MarshalJSON()
@@ -168,7 +149,7 @@ func (s *HazelcastStore) Update(oldObj *unstructured.Unstructured, newObj *unstr
 		return err
 	}
 
-	if err := cacheMap.Set(s.ctx, newObj.GetName(), serialization.JSON(json)); err != nil {
+	if err := cacheMap.Set(ctx, newObj.GetName(), serialization.JSON(json)); err != nil {
 		log.Error().
 			Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(newObj), "update", newObj)).
 			Err(err).
@@ -180,10 +161,10 @@ func (s *HazelcastStore) Update(oldObj *unstructured.Unstructured, newObj *unstr
 	return nil
 }
 
-func (s *HazelcastStore) Delete(obj *unstructured.Unstructured) error {
+func (s *HazelcastStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
 	cacheMap := s.getMap(obj)
 
-	if err := cacheMap.Delete(s.ctx, obj.GetName()); err != nil {
+	if err := cacheMap.Delete(ctx, obj.GetName()); err != nil {
 		log.Error().
 			Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "delete", obj)).
 			Err(err).
@@ -195,13 +176,13 @@ func (s *HazelcastStore) Delete(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (s *HazelcastStore) Read(gvr string, name string) (*unstructured.Unstructured, error) {
-	hzMap, err := s.client.GetMap(s.ctx, gvr)
+func (s *HazelcastStore) Read(ctx context.Context, gvr string, name string) (*unstructured.Unstructured, error) {
+	hzMap, err := s.client.GetMap(ctx, gvr)
 	if err != nil {
 		return nil, err
 	}
 
-	val, err := hzMap.Get(s.ctx, name)
+	val, err := hzMap.Get(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -229,28 +210,25 @@ func (s *HazelcastStore) Read(gvr string, name string) (*unstructured.Unstructur
 	return &obj, nil
 }
 
-func (s *HazelcastStore) List(name string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
-	hzMap, err := s.client.GetMap(s.ctx, name)
+func (s *HazelcastStore) List(ctx context.Context, name string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	hzMap, err := s.client.GetMap(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
-	keySet, err := hzMap.GetKeySet(s.ctx)
+	// fetching all entries at once avoids a round trip per entry, which matters for reconciliation of large maps
+	entries, err := hzMap.GetEntrySet(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []unstructured.Unstructured
 	count := int64(0)
-	for _, key := range keySet {
+	for _, entry := range entries {
 		if limit > 0 && count >= limit {
 			break
 		}
-		val, err := hzMap.Get(s.ctx, key)
-		if err != nil {
-			continue
-		}
-		jsonData, ok := val.(serialization.JSON)
+		jsonData, ok := entry.Value.(serialization.JSON)
 		if !ok {
 			continue
 		}
@@ -270,13 +248,13 @@ func (s *HazelcastStore) List(name string, fieldSelector string, limit int64) ([
 	return result, nil
 }
 
-func (s *HazelcastStore) Count(mapName string) (int, error) {
-	hzMap, err := s.client.GetMap(context.Background(), mapName)
+func (s *HazelcastStore) Count(ctx context.Context, mapName string) (int, error) {
+	hzMap, err := s.client.GetMap(ctx, mapName)
 	if err != nil {
 		return 0, err
 	}
 
-	size, err := hzMap.Size(context.Background())
+	size, err := hzMap.Size(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -284,13 +262,13 @@ func (s *HazelcastStore) Count(mapName string) (int, error) {
 	return size, err
 }
 
-func (s *HazelcastStore) Keys(mapName string) ([]string, error) {
-	hzMap, err := s.client.GetMap(context.Background(), mapName)
+func (s *HazelcastStore) Keys(ctx context.Context, mapName string) ([]string, error) {
+	hzMap, err := s.client.GetMap(ctx, mapName)
 	if err != nil {
 		return nil, err
 	}
 
-	keySet, err := hzMap.GetKeySet(context.Background())
+	keySet, err := hzMap.GetKeySet(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -386,32 +364,8 @@ func (s *HazelcastStore) onConnected() {
 		return
 	}
 
-	s.reconciliations.Range(func(key, value any) bool {
-		cacheName, _ := key.(string)
-		recon, ok := value.(*reconciler.Reconciliation)
-		if !ok {
-			log.Error().
-				Str("cache", cacheName).
-				Msg("Re-connect reconciliation object has unexpected type")
-			return true
-		}
-
-		defer func(cache string) {
-			if r := recover(); r != nil {
-				log.Error().
-					Str("cache", cache).
-					Interface("panic", r).
-					Msg("Panic occurred during re-connect reconciliation")
-			}
-		}(cacheName)
-
-		log.Debug().
-			Str("cache", cacheName).
-			Msg("Starting reconciliation after reconnect")
-
-		recon.SafeReconcile(s)
-		return true
-	})
+	log.Debug().Msg("Starting reconciliation after reconnect")
+	s.notifyReconnect()
 }
 
 func (s *HazelcastStore) onDisconnected() {
@@ -6,7 +6,6 @@ package store
 
 import (
 	"context"
-	"sync"
 	"testing"
 
 	"github.com/hazelcast/hazelcast-go-client"
@@ -47,7 +46,7 @@ func TestHazelcastStore_OnAdd(t *testing.T) {
 
 	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
 	for _, subscription := range subscriptions {
-		if err := hazelcastStore.Create(subscription); err != nil {
+		if err := hazelcastStore.Create(context.Background(), subscription); err != nil {
 			return
 		}
 		assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "could not write subscription %s", subscription.GetName())
@@ -71,7 +70,7 @@ func TestHazelcastStore_OnUpdate(t *testing.T) {
 		labels["hazelcast_test"] = "true"
 		updatedSubscription.SetLabels(labels)
 
-		if err := hazelcastStore.Update(subscription, updatedSubscription); err != nil {
+		if err := hazelcastStore.Update(context.Background(), subscription, updatedSubscription); err != nil {
 			return
 		}
 		assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "could not update subscription %s", subscription.GetName())
@@ -93,7 +92,7 @@ func TestHazelcastStore_OnDelete(t *testing.T) {
 
 	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
 	for _, subscription := range subscriptions {
-		if err := hazelcastStore.Delete(subscription); err != nil {
+		if err := hazelcastStore.Delete(context.Background(), subscription); err != nil {
 			return
 		}
 		assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "could not delete subscription %s", subscription.GetName())
@@ -114,7 +113,6 @@ func TestHazelcastStore_HandleClientEvents(t *testing.T) {
 
 	// Reset state
 	hazelcastStore.connected.Store(false)
-	hazelcastStore.reconciliations = sync.Map{}
 
 	// Simulate connected event
 	hazelcastStore.handleClientEvents(hazelcast.LifecycleStateChanged{State: hazelcast.LifecycleStateConnected})
@@ -129,36 +127,29 @@ func TestHazelcastStore_HandleClientEvents(t *testing.T) {
 	assertions.Equal(0, errorCount, "unexpected errors have been logged")
 }
 
-// Test to cover reconciliation iteration in onConnected
+// Test to cover the reconnect notification in onConnected
 func TestHazelcastStore_OnConnected(t *testing.T) {
 	assertions := assert.New(t)
 	defer test.LogRecorder.Reset()
 
 	// Reset state
 	hazelcastStore.connected.Store(false)
-	hazelcastStore.reconciliations = sync.Map{}
+	hazelcastStore.reconnectCallbacks = reconnectCallbacks{}
 
-	// Store a real Reconciliation object for the resource
-	testResource := config.Current.Resources[0]
-	kubernetesDataSource := reconciliation.NewDataSourceFromKubernetesClient(createFakeDynamicClient(), &testResource)
-	recon := reconciliation.NewReconciliation(
-		kubernetesDataSource,
-		&testResource,
-	)
-	cacheName := config.Current.Resources[0].GetGroupVersionName()
-	hazelcastStore.reconciliations.Store(cacheName, recon)
-
-	// Trigger onConnected should iterate and run reconciliation
+	reconnects := 0
+	hazelcastStore.OnReconnect(func() {
+		reconnects++
+	})
+
+	// Trigger onConnected should notify about the reconnect
 	hazelcastStore.onConnected()
-	assertions.True(hazelcastStore.connected.Load(), "connected should be true after onConnected with entry")
+	assertions.True(hazelcastStore.connected.Load(), "connected should be true after onConnected")
+	assertions.Equal(1, reconnects, "reconnect should be notified")
 
-	// Second call should keep reconOnce true and skip reconciliation
+	// Second call should skip the notification
 	hazelcastStore.onConnected()
 	assertions.True(hazelcastStore.connected.Load(), "connected should still be true after second onConnected")
-
-	// NOTE: With fake Kubernetes client, reconciliation may log errors due to mocked List() behavior
-	// This is expected and does not indicate a failure of onConnected() itself
-	// We just verify that onConnected was called and the connected flag is set correctly
+	assertions.Equal(1, reconnects, "reconnect should only be notified once")
 }
 
 // Test to cover the reset of reconOnce in onDisconnected
@@ -166,9 +157,6 @@ func TestHazelcastStore_OnDisconnected(t *testing.T) {
 	assertions := assert.New(t)
 	defer test.LogRecorder.Reset()
 
-	// Reset state
-	hazelcastStore.reconciliations = sync.Map{}
-
 	// Case: reconOnce false remains false
 	hazelcastStore.connected.Store(false)
 	hazelcastStore.onDisconnected()
@@ -216,7 +204,7 @@ func TestHazelcastStore_Count(t *testing.T) {
 	mapName := testResource.GetName()
 
 	// Get count from the store
-	count, err := hazelcastStore.Count(mapName)
+	count, err := hazelcastStore.Count(context.Background(), mapName)
 
 	// May fail if map doesn't exist, but should not panic
 	if err != nil {
@@ -244,7 +232,7 @@ func TestHazelcastStore_Keys(t *testing.T) {
 	testResource := subscriptions[0]
 	mapName := testResource.GetName()
 
-	keys, err := hazelcastStore.Keys(mapName)
+	keys, err := hazelcastStore.Keys(context.Background(), mapName)
 
 	// May fail if map doesn't exist, but should not panic
 	if err != nil {
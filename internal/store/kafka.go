@@ -0,0 +1,224 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type KafkaEventType string
+
+const (
+	KafkaEventCreate KafkaEventType = "create"
+	KafkaEventUpdate KafkaEventType = "update"
+	KafkaEventDelete KafkaEventType = "delete"
+
+	kafkaEventTypeHeader = "quasar-event-type"
+)
+
+// KafkaEvent is the value of every record published by the KafkaStore.
+type KafkaEvent struct {
+	Type      KafkaEventType `json:"type"`
+	Dataset   string         `json:"dataset"`
+	Key       string         `json:"key"`
+	OldObject map[string]any `json:"oldObject,omitempty"`
+	NewObject map[string]any `json:"newObject,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// KafkaStore is a write-only store that publishes a change event for every write instead of storing the resource.
+// Records are keyed by "<namespace>/<name>" so that all events of a resource end up in the same partition
+// and compacted topics keep the latest state of each resource. It can only be used as sink.
+type KafkaStore struct {
+	producer  sarama.SyncProducer
+	connected atomic.Bool
+}
+
+func (k *KafkaStore) Initialize() {
+	var err error
+	k.producer, err = newKafkaProducer(&config.Current.Store.Kafka)
+	if err != nil {
+		log.Fatal().Err(err).Strs("brokers", config.Current.Store.Kafka.Brokers).Msg("Could not create kafka producer")
+		return
+	}
+
+	k.connected.Store(true)
+	log.Info().Strs("brokers", config.Current.Store.Kafka.Brokers).Msg("Kafka producer created")
+}
+
+func (k *KafkaStore) InitializeResource(dataSource reconciliation.DataSource, resourceConfig *config.Resource) {
+	_ = dataSource
+	resource := resourceConfig.GetGroupVersionResource()
+	log.Debug().Fields(utils.CreateFieldForResource(&resource)).
+		Str("topic", resourceConfig.KafkaTopic).
+		Msg("Publishing resource changes to kafka")
+}
+
+func (k *KafkaStore) Create(_ context.Context, obj *unstructured.Unstructured) error {
+	return k.publish(KafkaEventCreate, nil, obj)
+}
+
+func (k *KafkaStore) Update(_ context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	return k.publish(KafkaEventUpdate, oldObj, newObj)
+}
+
+func (k *KafkaStore) Delete(_ context.Context, obj *unstructured.Unstructured) error {
+	return k.publish(KafkaEventDelete, obj, nil)
+}
+
+func (k *KafkaStore) Count(_ context.Context, dataset string) (int, error) {
+	return 0, ErrWriteOnlyStore
+}
+
+func (k *KafkaStore) Keys(_ context.Context, dataset string) ([]string, error) {
+	return nil, ErrWriteOnlyStore
+}
+
+func (k *KafkaStore) Read(_ context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	return nil, ErrWriteOnlyStore
+}
+
+func (k *KafkaStore) List(_ context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	return nil, ErrWriteOnlyStore
+}
+
+func (k *KafkaStore) Shutdown() {
+	if k.producer != nil {
+		if err := k.producer.Close(); err != nil {
+			log.Error().Err(err).Msg("Could not close kafka producer")
+		}
+	}
+	k.connected.Store(false)
+}
+
+func (k *KafkaStore) Connected() bool {
+	return k.connected.Load()
+}
+
+func (k *KafkaStore) WriteOnly() bool {
+	return true
+}
+
+func (k *KafkaStore) publish(eventType KafkaEventType, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	obj := newObj
+	if obj == nil {
+		obj = oldObj
+	}
+	dataset := utils.GetGroupVersionId(obj)
+
+	messages, err := createKafkaMessages(eventType, oldObj, newObj, config.Current.Store.Kafka.Tombstones)
+	if err == nil {
+		err = k.producer.SendMessages(messages)
+	}
+
+	if err != nil {
+		k.connected.Store(false)
+		metrics.GetOrCreateCustomCounter("kafka_publish_errors_total").WithLabelValues().Inc()
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(dataset, string(eventType), obj)).
+			Msg("Could not publish resource change to kafka")
+		return err
+	}
+
+	k.connected.Store(true)
+	metrics.GetOrCreateCustomCounter("kafka_published_events_total").WithLabelValues().Inc()
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(dataset, string(eventType), obj)).
+		Str("topic", messages[0].Topic).
+		Msg("Resource change published to kafka")
+	return nil
+}
+
+// createKafkaMessages creates the record of an event and, for deletions with tombstones enabled, an additional
+// record without value that allows compacted topics to eventually drop the resource.
+func createKafkaMessages(
+	eventType KafkaEventType,
+	oldObj *unstructured.Unstructured,
+	newObj *unstructured.Unstructured,
+	tombstones bool,
+) ([]*sarama.ProducerMessage, error) {
+	obj := newObj
+	if obj == nil {
+		obj = oldObj
+	}
+
+	event := KafkaEvent{
+		Type:      eventType,
+		Dataset:   utils.GetGroupVersionId(obj),
+		Key:       namespacedName(obj),
+		Timestamp: time.Now().UTC(),
+	}
+	if oldObj != nil {
+		event.OldObject = oldObj.Object
+	}
+	if newObj != nil {
+		event.NewObject = newObj.Object
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := kafkaTopic(obj)
+	messages := []*sarama.ProducerMessage{{
+		Topic: topic,
+		Key:   sarama.StringEncoder(event.Key),
+		Value: sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(kafkaEventTypeHeader), Value: []byte(eventType)},
+		},
+	}}
+
+	if eventType == KafkaEventDelete && tombstones {
+		messages = append(messages, &sarama.ProducerMessage{
+			Topic: topic,
+			Key:   sarama.StringEncoder(event.Key),
+		})
+	}
+
+	return messages, nil
+}
+
+// kafkaTopic returns the topic configured for the resource or falls back to the name of the dataset.
+func kafkaTopic(obj *unstructured.Unstructured) string {
+	if resourceConfig, ok := config.Current.GetResourceConfiguration(obj); ok && resourceConfig.KafkaTopic != "" {
+		return resourceConfig.KafkaTopic
+	}
+	return utils.GetGroupVersionId(obj)
+}
+
+// namespacedName returns "<namespace>/<name>" of the resource, or only its name if it is cluster-scoped.
+func namespacedName(obj *unstructured.Unstructured) string {
+	if namespace := obj.GetNamespace(); namespace != "" {
+		return namespace + "/" + obj.GetName()
+	}
+	return obj.GetName()
+}
+
+func newKafkaProducer(kafkaConfig *config.Kafka) (sarama.SyncProducer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = kafkaConfig.ClientId
+	if kafkaConfig.Timeout > 0 {
+		saramaConfig.Net.DialTimeout = kafkaConfig.Timeout
+		saramaConfig.Producer.Timeout = kafkaConfig.Timeout
+	}
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	saramaConfig.Producer.Partitioner = sarama.NewHashPartitioner
+	saramaConfig.Producer.Return.Successes = true
+
+	return sarama.NewSyncProducer(kafkaConfig.Brokers, saramaConfig)
+}
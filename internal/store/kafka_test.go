@@ -0,0 +1,130 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+)
+
+func TestKafkaStore_Publish(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	topic := utils.GetGroupVersionId(subscriptions[0])
+
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+
+	previousKafkaConfig := config.Current.Store.Kafka
+	config.Current.Store.Kafka = config.Kafka{
+		Brokers:  []string{broker.Addr()},
+		ClientId: "quasar-test",
+		Timeout:  5 * time.Second,
+	}
+	defer func() {
+		config.Current.Store.Kafka = previousKafkaConfig
+	}()
+
+	kafkaStore := new(KafkaStore)
+	kafkaStore.Initialize()
+	defer kafkaStore.Shutdown()
+
+	assertions.NoError(kafkaStore.Create(context.Background(), subscriptions[0]))
+	assertions.NoError(kafkaStore.Update(context.Background(), subscriptions[0], subscriptions[0]))
+	assertions.NoError(kafkaStore.Delete(context.Background(), subscriptions[0]))
+	assertions.True(kafkaStore.Connected())
+
+	produceRequests := 0
+	for _, requestResponse := range broker.History() {
+		if _, ok := requestResponse.Request.(*sarama.ProduceRequest); ok {
+			produceRequests++
+		}
+	}
+	assertions.Equal(3, produceRequests, "every write should be published")
+
+	_, err := kafkaStore.Count(context.Background(), topic)
+	assertions.ErrorIs(err, ErrWriteOnlyStore)
+	_, err = kafkaStore.Read(context.Background(), topic, subscriptions[0].GetName())
+	assertions.ErrorIs(err, ErrWriteOnlyStore)
+}
+
+func TestCreateKafkaMessages(t *testing.T) {
+	assertions := assert.New(t)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	updated := subscriptions[0].DeepCopy()
+	updated.SetLabels(map[string]string{"kafka_test": "true"})
+
+	messages, err := createKafkaMessages(KafkaEventUpdate, subscriptions[0], updated, true)
+	assertions.NoError(err)
+	if assertions.Len(messages, 1, "only deletions should produce tombstones") {
+		key, _ := messages[0].Key.Encode()
+		assertions.Equal("playground/"+subscriptions[0].GetName(), string(key))
+		assertions.Equal(utils.GetGroupVersionId(subscriptions[0]), messages[0].Topic)
+
+		value, _ := messages[0].Value.Encode()
+		var event KafkaEvent
+		assertions.NoError(json.Unmarshal(value, &event))
+		assertions.Equal(KafkaEventUpdate, event.Type)
+		assertions.NotNil(event.OldObject)
+		assertions.NotNil(event.NewObject)
+	}
+
+	messages, err = createKafkaMessages(KafkaEventDelete, subscriptions[0], nil, true)
+	assertions.NoError(err)
+	if assertions.Len(messages, 2, "deletion should produce an event and a tombstone") {
+		assertions.Equal(messages[0].Key, messages[1].Key)
+		assertions.Nil(messages[1].Value)
+	}
+
+	messages, err = createKafkaMessages(KafkaEventDelete, subscriptions[0], nil, false)
+	assertions.NoError(err)
+	assertions.Len(messages, 1)
+}
+
+func TestKafkaTopic_ResourceConfiguration(t *testing.T) {
+	assertions := assert.New(t)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+
+	previousResources := config.Current.Resources
+	defer func() {
+		config.Current.Resources = previousResources
+	}()
+
+	resourceConfig := config.Resource{KafkaTopic: "horizon.subscriptions"}
+	resourceConfig.Kubernetes.Group = "subscriber.horizon.telekom.de"
+	resourceConfig.Kubernetes.Version = "v1"
+	resourceConfig.Kubernetes.Kind = subscriptions[0].GetKind()
+	config.Current.Resources = append(config.Current.Resources, resourceConfig)
+
+	assertions.Equal("horizon.subscriptions", kafkaTopic(subscriptions[0]))
+}
+
+func TestIsWriteOnly(t *testing.T) {
+	assertions := assert.New(t)
+
+	assertions.True(isWriteOnly(new(KafkaStore)))
+	assertions.False(isWriteOnly(new(MemoryStore)))
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	reconciler "github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// MemoryStore keeps all datasets in process. It is meant for local development and tests and can optionally
+// persist its content to a snapshot file on shutdown, which is restored on the next start.
+type MemoryStore struct {
+	datasets map[string]*memoryDataset
+	mu       sync.RWMutex
+}
+
+// memoryDataset holds the resources of a single dataset by name and maintains value indexes for
+// the configured field paths to speed up field-selector lookups.
+type memoryDataset struct {
+	items   map[string]*unstructured.Unstructured
+	indexes map[string]map[string]map[string]struct{}
+	mu      sync.RWMutex
+}
+
+func newMemoryDataset() *memoryDataset {
+	return &memoryDataset{
+		items:   make(map[string]*unstructured.Unstructured),
+		indexes: make(map[string]map[string]map[string]struct{}),
+	}
+}
+
+func (s *MemoryStore) Initialize() {
+	s.datasets = make(map[string]*memoryDataset)
+
+	snapshotPath := config.Current.Store.Memory.SnapshotPath
+	if snapshotPath == "" {
+		log.Info().Msg("In-memory store initialized")
+		return
+	}
+
+	restored, err := s.restoreSnapshot(snapshotPath)
+	if err != nil {
+		log.Error().Err(err).Str("snapshotPath", snapshotPath).Msg("Could not restore in-memory store from snapshot")
+		return
+	}
+
+	log.Info().Str("snapshotPath", snapshotPath).Int("restored", restored).Msg("In-memory store initialized from snapshot")
+}
+
+func (s *MemoryStore) InitializeResource(_ reconciler.DataSource, resourceConfig *config.Resource) {
+	dataset := s.getDataset(resourceConfig.GetGroupVersionName())
+	dataset.addIndexes(resourceConfig.MemoryIndexes)
+}
+
+func (s *MemoryStore) Create(_ context.Context, obj *unstructured.Unstructured) error {
+	s.getDataset(utils.GetGroupVersionId(obj)).put(obj.DeepCopy())
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "create", obj)).
+		Msg("Resource created or updated in memory")
+	return nil
+}
+
+func (s *MemoryStore) Update(_ context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	dataset := s.getDataset(utils.GetGroupVersionId(newObj))
+	if oldObj.GetName() != newObj.GetName() {
+		dataset.remove(oldObj.GetName())
+	}
+	dataset.put(newObj.DeepCopy())
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(newObj), "update", newObj)).
+		Msg("Resource updated in memory")
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, obj *unstructured.Unstructured) error {
+	s.getDataset(utils.GetGroupVersionId(obj)).remove(obj.GetName())
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "delete", obj)).
+		Msg("Resource deleted in memory")
+	return nil
+}
+
+func (s *MemoryStore) Count(_ context.Context, dataset string) (int, error) {
+	ds := s.getDataset(dataset)
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	return len(ds.items), nil
+}
+
+func (s *MemoryStore) Keys(_ context.Context, dataset string) ([]string, error) {
+	ds := s.getDataset(dataset)
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	keys := make([]string, 0, len(ds.items))
+	for key := range ds.items {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+func (s *MemoryStore) Read(_ context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	ds := s.getDataset(dataset)
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	obj, ok := ds.items[key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	return obj.DeepCopy(), nil
+}
+
+func (s *MemoryStore) List(_ context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	ds := s.getDataset(dataset)
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	fields := utils.ParseFieldSelector(fieldSelector)
+
+	var result []unstructured.Unstructured
+	for _, key := range ds.candidates(fields) {
+		obj := ds.items[key]
+		if !utils.MatchFields(obj, fields) {
+			continue
+		}
+
+		result = append(result, *obj.DeepCopy())
+		if limit > 0 && int64(len(result)) >= limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) Shutdown() {
+	snapshotPath := config.Current.Store.Memory.SnapshotPath
+	if snapshotPath == "" {
+		return
+	}
+
+	if err := s.writeSnapshot(snapshotPath); err != nil {
+		log.Error().Err(err).Str("snapshotPath", snapshotPath).Msg("Could not write snapshot of in-memory store")
+		return
+	}
+	log.Info().Str("snapshotPath", snapshotPath).Msg("Wrote snapshot of in-memory store")
+}
+
+func (s *MemoryStore) Connected() bool { return true }
+
+func (s *MemoryStore) getDataset(name string) *memoryDataset {
+	s.mu.RLock()
+	dataset, ok := s.datasets[name]
+	s.mu.RUnlock()
+	if ok {
+		return dataset
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if dataset, ok = s.datasets[name]; !ok {
+		dataset = newMemoryDataset()
+		s.datasets[name] = dataset
+	}
+	return dataset
+}
+
+func (s *MemoryStore) restoreSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var snapshot map[string][]map[string]any
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("could not parse snapshot: %w", err)
+	}
+
+	restored := 0
+	for name, items := range snapshot {
+		dataset := s.getDataset(name)
+		for _, item := range items {
+			dataset.put(&unstructured.Unstructured{Object: item})
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+func (s *MemoryStore) writeSnapshot(path string) error {
+	s.mu.RLock()
+	snapshot := make(map[string][]map[string]any, len(s.datasets))
+	for name, dataset := range s.datasets {
+		dataset.mu.RLock()
+		items := make([]map[string]any, 0, len(dataset.items))
+		for _, obj := range dataset.items {
+			items = append(items, obj.Object)
+		}
+		snapshot[name] = items
+		dataset.mu.RUnlock()
+	}
+	s.mu.RUnlock()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a crash never leaves a truncated snapshot behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (d *memoryDataset) addIndexes(paths []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, path := range paths {
+		path = strings.TrimPrefix(path, ".")
+		if _, ok := d.indexes[path]; ok {
+			continue
+		}
+
+		index := make(map[string]map[string]struct{})
+		for key, obj := range d.items {
+			if value, ok := indexValue(obj, path); ok {
+				addToIndex(index, value, key)
+			}
+		}
+		d.indexes[path] = index
+	}
+}
+
+func (d *memoryDataset) put(obj *unstructured.Unstructured) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := obj.GetName()
+	if previous, ok := d.items[key]; ok {
+		d.unindex(key, previous)
+	}
+
+	d.items[key] = obj
+	for path, index := range d.indexes {
+		if value, ok := indexValue(obj, path); ok {
+			addToIndex(index, value, key)
+		}
+	}
+}
+
+func (d *memoryDataset) remove(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if previous, ok := d.items[key]; ok {
+		d.unindex(key, previous)
+		delete(d.items, key)
+	}
+}
+
+func (d *memoryDataset) unindex(key string, obj *unstructured.Unstructured) {
+	for path, index := range d.indexes {
+		value, ok := indexValue(obj, path)
+		if !ok {
+			continue
+		}
+
+		delete(index[value], key)
+		if len(index[value]) == 0 {
+			delete(index, value)
+		}
+	}
+}
+
+// candidates returns the sorted keys that may match the given fields, narrowed down by the first indexed field.
+// Callers must hold the read lock.
+func (d *memoryDataset) candidates(fields map[string]string) []string {
+	var keys []string
+
+	indexed := false
+	for path, value := range fields {
+		index, ok := d.indexes[path]
+		if !ok {
+			continue
+		}
+
+		indexed = true
+		for key := range index[value] {
+			keys = append(keys, key)
+		}
+		break
+	}
+
+	if !indexed {
+		keys = make([]string, 0, len(d.items))
+		for key := range d.items {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+func indexValue(obj *unstructured.Unstructured, path string) (string, bool) {
+	value, ok, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(path, ".")...)
+	if err != nil || !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%v", value), true
+}
+
+func addToIndex(index map[string]map[string]struct{}, value string, key string) {
+	keys, ok := index[value]
+	if !ok {
+		keys = make(map[string]struct{})
+		index[value] = keys
+	}
+	keys[key] = struct{}{}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+)
+
+func createMemoryStore(t *testing.T) *MemoryStore {
+	t.Helper()
+
+	memoryStore := new(MemoryStore)
+	memoryStore.Initialize()
+
+	for _, subscription := range test.ReadTestSubscriptions("../../testdata/subscriptions.json") {
+		if err := memoryStore.Create(context.Background(), subscription); err != nil {
+			t.Fatalf("could not write subscription %s: %s", subscription.GetName(), err)
+		}
+	}
+	return memoryStore
+}
+
+func TestMemoryStore_CRUD(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	memoryStore := createMemoryStore(t)
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	count, err := memoryStore.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count)
+
+	keys, err := memoryStore.Keys(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.ElementsMatch([]string{subscriptions[0].GetName(), subscriptions[1].GetName()}, keys)
+
+	updated := subscriptions[0].DeepCopy()
+	updated.SetLabels(map[string]string{"memory_test": "true"})
+	assertions.NoError(memoryStore.Update(context.Background(), subscriptions[0], updated))
+
+	obj, err := memoryStore.Read(context.Background(), dataset, subscriptions[0].GetName())
+	assertions.NoError(err)
+	assertions.Equal("true", obj.GetLabels()["memory_test"])
+
+	// modifying a returned object must not modify the store
+	obj.SetLabels(nil)
+	obj, _ = memoryStore.Read(context.Background(), dataset, subscriptions[0].GetName())
+	assertions.Equal("true", obj.GetLabels()["memory_test"])
+
+	assertions.NoError(memoryStore.Delete(context.Background(), subscriptions[0]))
+	_, err = memoryStore.Read(context.Background(), dataset, subscriptions[0].GetName())
+	assertions.ErrorIs(err, ErrResourceNotFound)
+
+	count, _ = memoryStore.Count(context.Background(), dataset)
+	assertions.Equal(len(subscriptions)-1, count)
+}
+
+func TestMemoryStore_List(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	memoryStore := createMemoryStore(t)
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	items, err := memoryStore.List(context.Background(), dataset, "", 0)
+	assertions.NoError(err)
+	assertions.Len(items, len(subscriptions))
+
+	items, err = memoryStore.List(context.Background(), dataset, "", 1)
+	assertions.NoError(err)
+	assertions.Len(items, 1, "limit should be respected")
+
+	subscriptionId := subscriptions[1].GetName()
+	selector := "spec.subscription.subscriptionId=" + subscriptionId
+
+	items, err = memoryStore.List(context.Background(), dataset, selector, 0)
+	assertions.NoError(err)
+	if assertions.Len(items, 1, "unindexed field selector should match exactly one item") {
+		assertions.Equal(subscriptionId, items[0].GetName())
+	}
+
+	memoryStore.getDataset(dataset).addIndexes([]string{"spec.subscription.subscriptionId"})
+	items, err = memoryStore.List(context.Background(), dataset, selector+",metadata.namespace=playground", 0)
+	assertions.NoError(err)
+	if assertions.Len(items, 1, "indexed field selector should match exactly one item") {
+		assertions.Equal(subscriptionId, items[0].GetName())
+	}
+
+	items, err = memoryStore.List(context.Background(), dataset, "spec.subscription.subscriptionId=does-not-exist", 0)
+	assertions.NoError(err)
+	assertions.Empty(items)
+
+	assertions.NoError(memoryStore.Delete(context.Background(), subscriptions[1]))
+	items, err = memoryStore.List(context.Background(), dataset, selector, 0)
+	assertions.NoError(err)
+	assertions.Empty(items, "index should be updated on delete")
+}
+
+func TestMemoryStore_Snapshot(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	previousPath := config.Current.Store.Memory.SnapshotPath
+	config.Current.Store.Memory.SnapshotPath = filepath.Join(t.TempDir(), "snapshot.json")
+	defer func() {
+		config.Current.Store.Memory.SnapshotPath = previousPath
+	}()
+
+	memoryStore := createMemoryStore(t)
+	memoryStore.Shutdown()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	restoredStore := new(MemoryStore)
+	restoredStore.Initialize()
+
+	count, err := restoredStore.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count, "snapshot should restore all resources")
+
+	obj, err := restoredStore.Read(context.Background(), dataset, subscriptions[0].GetName())
+	assertions.NoError(err)
+	assertions.Equal(subscriptions[0].GetUID(), obj.GetUID())
+}
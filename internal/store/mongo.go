@@ -89,7 +89,7 @@ func (m *MongoStore) collectMetrics(resourceName string) {
 	}
 }
 
-func (m *MongoStore) Create(obj *unstructured.Unstructured) error {
+func (m *MongoStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
 	collectionName := utils.GetGroupVersionId(obj)
 
 	filter, err := m.createFilter(obj)
@@ -101,7 +101,7 @@ func (m *MongoStore) Create(obj *unstructured.Unstructured) error {
 	}
 
 	opts := options.Replace().SetUpsert(true)
-	_, err = m.getCollection(obj).ReplaceOne(m.ctx, filter, obj.Object, opts)
+	_, err = m.getCollection(obj).ReplaceOne(ctx, filter, obj.Object, opts)
 	if err != nil {
 		log.Error().Err(err).
 			Fields(utils.CreateFieldsForCollection(collectionName, "create", obj)).
@@ -115,7 +115,7 @@ func (m *MongoStore) Create(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (m *MongoStore) Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+func (m *MongoStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
 	collectionName := utils.GetGroupVersionId(oldObj)
 
 	filter, err := m.createFilter(oldObj)
@@ -127,7 +127,7 @@ func (m *MongoStore) Update(oldObj *unstructured.Unstructured, newObj *unstructu
 	}
 
 	opts := options.Replace().SetUpsert(true)
-	_, err = m.getCollection(oldObj).ReplaceOne(m.ctx, filter, newObj.Object, opts)
+	_, err = m.getCollection(oldObj).ReplaceOne(ctx, filter, newObj.Object, opts)
 	if err != nil {
 		log.Error().Err(err).
 			Fields(utils.CreateFieldsForCollection(collectionName, "update", oldObj)).
@@ -141,7 +141,7 @@ func (m *MongoStore) Update(oldObj *unstructured.Unstructured, newObj *unstructu
 	return nil
 }
 
-func (m *MongoStore) Delete(obj *unstructured.Unstructured) error {
+func (m *MongoStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
 	collectionName := utils.GetGroupVersionId(obj)
 
 	filter, err := m.createFilter(obj)
@@ -152,7 +152,7 @@ func (m *MongoStore) Delete(obj *unstructured.Unstructured) error {
 		return err
 	}
 
-	_, err = m.getCollection(obj).DeleteOne(m.ctx, filter)
+	_, err = m.getCollection(obj).DeleteOne(ctx, filter)
 	if err != nil {
 		log.Error().Err(err).
 			Fields(utils.CreateFieldsForCollection(collectionName, "delete", obj)).
@@ -166,10 +166,10 @@ func (m *MongoStore) Delete(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (m *MongoStore) Count(collectionName string) (int, error) {
+func (m *MongoStore) Count(ctx context.Context, collectionName string) (int, error) {
 	collection := m.client.Database(config.Current.Store.Mongo.Database).Collection(collectionName)
 
-	count, err := collection.CountDocuments(m.ctx, bson.M{})
+	count, err := collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
 		log.Error().Err(err).
 			Fields(utils.CreateFieldsForCollection(collectionName, "count", nil)).
@@ -184,10 +184,10 @@ func (m *MongoStore) Count(collectionName string) (int, error) {
 	return int(count), nil
 }
 
-func (m *MongoStore) Keys(collectionName string) ([]string, error) {
+func (m *MongoStore) Keys(ctx context.Context, collectionName string) ([]string, error) {
 	collection := m.client.Database(config.Current.Store.Mongo.Database).Collection(collectionName)
 
-	keys, err := collection.Distinct(m.ctx, "_id", bson.M{})
+	keys, err := collection.Distinct(ctx, "_id", bson.M{})
 	if err != nil {
 		log.Error().Err(err).
 			Fields(utils.CreateFieldsForCollection(collectionName, "keys", nil)).
@@ -211,13 +211,13 @@ func (m *MongoStore) Keys(collectionName string) ([]string, error) {
 	return stringKeys, nil
 }
 
-func (m *MongoStore) Read(collectionName string, key string) (*unstructured.Unstructured, error) {
+func (m *MongoStore) Read(ctx context.Context, collectionName string, key string) (*unstructured.Unstructured, error) {
 	collection := m.client.Database(config.Current.Store.Mongo.Database).Collection(collectionName)
 
 	filter := bson.M{"_id": key}
 	var result unstructured.Unstructured
 
-	err := collection.FindOne(m.ctx, filter).Decode(&result.Object)
+	err := collection.FindOne(ctx, filter).Decode(&result.Object)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrResourceNotFound
@@ -237,7 +237,7 @@ func (m *MongoStore) Read(collectionName string, key string) (*unstructured.Unst
 	return &result, nil
 }
 
-func (m *MongoStore) List(collectionName string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+func (m *MongoStore) List(ctx context.Context, collectionName string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
 	collection := m.client.Database(config.Current.Store.Mongo.Database).Collection(collectionName)
 	filter := bson.M{}
 
@@ -255,7 +255,7 @@ func (m *MongoStore) List(collectionName string, fieldSelector string, limit int
 		findOptions.SetLimit(limit)
 	}
 
-	cursor, err := collection.Find(m.ctx, filter, findOptions)
+	cursor, err := collection.Find(ctx, filter, findOptions)
 	if err != nil {
 		log.Error().Err(err).
 			Fields(utils.CreateFieldsForCollectionWithListOptions(collectionName, "list", nil, limit, fieldSelector)).
@@ -266,10 +266,10 @@ func (m *MongoStore) List(collectionName string, fieldSelector string, limit int
 		if err := cursor.Close(ctx); err != nil {
 			return
 		}
-	}(cursor, m.ctx)
+	}(cursor, ctx)
 
 	var results []unstructured.Unstructured
-	for cursor.Next(m.ctx) {
+	for cursor.Next(ctx) {
 		var resource unstructured.Unstructured
 		if err := cursor.Decode(&resource.Object); err != nil {
 			log.Error().Err(err).
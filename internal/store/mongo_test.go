@@ -105,7 +105,7 @@ func TestMongoStore_Create(t *testing.T) {
 
 	resource := test.CreateTestResource("test-resource", "default", map[string]string{"app": "test"})
 
-	err := store.Create(resource)
+	err := store.Create(context.Background(), resource)
 	assertions.NoError(err)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
 
@@ -126,7 +126,7 @@ func TestMongoStore_Create(t *testing.T) {
 	assertions.Equal("test", labels["app"])
 
 	resource.SetLabels(map[string]string{"app": "updated"})
-	err = store.Create(resource)
+	err = store.Create(context.Background(), resource)
 	assertions.NoError(err)
 
 	err = collection.FindOne(context.Background(), filter).Decode(&result)
@@ -151,7 +151,7 @@ func TestMongoStore_Update(t *testing.T) {
 
 	oldResource := test.CreateTestResource("test-resource", "default", map[string]string{"app": "test"})
 
-	err := store.Create(oldResource)
+	err := store.Create(context.Background(), oldResource)
 	assertions.NoError(err)
 
 	newResource := test.CreateTestResource("test-resource", "default", map[string]string{"app": "updated"})
@@ -159,7 +159,7 @@ func TestMongoStore_Update(t *testing.T) {
 		"replicas": 3,
 	}
 
-	err = store.Update(oldResource, newResource)
+	err = store.Update(context.Background(), oldResource, newResource)
 	assertions.NoError(err)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
 
@@ -190,10 +190,10 @@ func TestMongoStore_Delete(t *testing.T) {
 
 	resource := test.CreateTestResource("test-resource", "default", nil)
 
-	err := store.Create(resource)
+	err := store.Create(context.Background(), resource)
 	assertions.NoError(err)
 
-	err = store.Delete(resource)
+	err = store.Delete(context.Background(), resource)
 	assertions.NoError(err)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
 
@@ -212,17 +212,17 @@ func TestMongoStore_Count(t *testing.T) {
 	store := setupMongoStore()
 	cleanupMongoCollection()
 
-	count, err := store.Count(testCollectionName)
+	count, err := store.Count(context.Background(), testCollectionName)
 	assertions.NoError(err)
 	assertions.Equal(0, count)
 
 	for i := 1; i <= 3; i++ {
 		resource := test.CreateTestResource(fmt.Sprintf("test-resource-%d", i), "default", nil)
-		err = store.Create(resource)
+		err = store.Create(context.Background(), resource)
 		assertions.NoError(err)
 	}
 
-	count, err = store.Count(testCollectionName)
+	count, err = store.Count(context.Background(), testCollectionName)
 	assertions.NoError(err)
 	assertions.Equal(3, count)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
@@ -235,7 +235,7 @@ func TestMongoStore_Keys(t *testing.T) {
 	store := setupMongoStore()
 	cleanupMongoCollection()
 
-	keys, err := store.Keys(testCollectionName)
+	keys, err := store.Keys(context.Background(), testCollectionName)
 	assertions.NoError(err)
 	assertions.Empty(keys)
 
@@ -247,11 +247,11 @@ func TestMongoStore_Keys(t *testing.T) {
 
 	for i := 1; i <= 3; i++ {
 		resource := test.CreateTestResource(fmt.Sprintf("test-resource-%d", i), "default", nil)
-		err = store.Create(resource)
+		err = store.Create(context.Background(), resource)
 		assertions.NoError(err)
 	}
 
-	keys, err = store.Keys(testCollectionName)
+	keys, err = store.Keys(context.Background(), testCollectionName)
 	assertions.NoError(err)
 	assertions.ElementsMatch(expectedKeys, keys)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
@@ -269,10 +269,10 @@ func TestMongoStore_Read(t *testing.T) {
 		"replicas": 2,
 	}
 
-	err := store.Create(resource)
+	err := store.Create(context.Background(), resource)
 	assertions.NoError(err)
 
-	result, err := store.Read(testCollectionName, "default/test-resource")
+	result, err := store.Read(context.Background(), testCollectionName, "default/test-resource")
 	assertions.NoError(err)
 	assertions.NotNil(result)
 
@@ -280,7 +280,7 @@ func TestMongoStore_Read(t *testing.T) {
 	assertions.Equal("default", result.GetNamespace())
 	assertions.Equal("test", result.GetLabels()["app"])
 
-	result, err = store.Read(testCollectionName, "non-existent")
+	result, err = store.Read(context.Background(), testCollectionName, "non-existent")
 	assertions.ErrorIs(err, ErrResourceNotFound)
 	assertions.Nil(result)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
@@ -301,19 +301,19 @@ func TestMongoStore_List(t *testing.T) {
 
 	for i, label := range labels {
 		resource := test.CreateTestResource(fmt.Sprintf("test-resource-%d", i+1), "default", label)
-		err := store.Create(resource)
+		err := store.Create(context.Background(), resource)
 		assertions.NoError(err)
 	}
 
-	results, err := store.List(testCollectionName, "", 0)
+	results, err := store.List(context.Background(), testCollectionName, "", 0)
 	assertions.NoError(err)
 	assertions.Len(results, 3)
 
-	results, err = store.List(testCollectionName, "metadata.labels.app=frontend", 0)
+	results, err = store.List(context.Background(), testCollectionName, "metadata.labels.app=frontend", 0)
 	assertions.NoError(err)
 	assertions.Len(results, 2)
 
-	results, err = store.List(testCollectionName, "metadata.labels.env=prod", 1)
+	results, err = store.List(context.Background(), testCollectionName, "metadata.labels.env=prod", 1)
 	assertions.NoError(err)
 	assertions.Len(results, 1)
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
@@ -463,13 +463,13 @@ func TestMongoStore_OperationWithBadObject(t *testing.T) {
 		},
 	}
 
-	err := store.Create(badObject)
+	err := store.Create(context.Background(), badObject)
 	assertions.NoError(err, "Create should succeed with invalid metadata as UID is used for ID")
 
-	err = store.Update(badObject, badObject)
+	err = store.Update(context.Background(), badObject, badObject)
 	assertions.NoError(err, "Update should succeed with invalid metadata")
 
-	err = store.Delete(badObject)
+	err = store.Delete(context.Background(), badObject)
 	assertions.NoError(err, "Delete should succeed with invalid metadata")
 
 	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "no errors should be logged")
@@ -481,19 +481,19 @@ func TestMongoStore_ErrorHandling(t *testing.T) {
 
 	store := setupMongoStore()
 
-	count, err := store.Count("non_existent_collection")
+	count, err := store.Count(context.Background(), "non_existent_collection")
 	assertions.NoError(err)
 	assertions.Equal(0, count)
 
-	keys, err := store.Keys("non_existent_collection")
+	keys, err := store.Keys(context.Background(), "non_existent_collection")
 	assertions.NoError(err)
 	assertions.Empty(keys)
 
-	result, err := store.Read(testCollectionName, "")
+	result, err := store.Read(context.Background(), testCollectionName, "")
 	assertions.ErrorIs(err, ErrResourceNotFound)
 	assertions.Nil(result)
 
-	results, err := store.List("non_existent_collection", "", 0)
+	results, err := store.List(context.Background(), "non_existent_collection", "", 0)
 	assertions.NoError(err)
 
 	// List returns empty slice for empty collection, not nil
@@ -501,7 +501,7 @@ func TestMongoStore_ErrorHandling(t *testing.T) {
 		assertions.Empty(results)
 	}
 
-	results, err = store.List(testCollectionName, "invalid-selector", 0)
+	results, err = store.List(context.Background(), testCollectionName, "invalid-selector", 0)
 	assertions.NoError(err)
 
 	// Should return empty slice if selector is invalid and collection is empty
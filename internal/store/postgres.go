@@ -0,0 +1,461 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	postgresHealthCheckInterval = 5 * time.Second
+
+	// postgresUndefinedTable is the SQLSTATE returned when a table does not exist (yet).
+	postgresUndefinedTable = "42P01"
+)
+
+// PostgresStore keeps every dataset in its own table, which holds the resources as JSONB documents in the
+// "data" column keyed like the MongoStore. Field selectors are translated into "data #>> '{path}'" expressions,
+// which are also the expressions the configured postgresIndexes are created on.
+type PostgresStore struct {
+	pool      *pgxpool.Pool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	tables    sync.Map
+	connected atomic.Bool
+}
+
+func (p *PostgresStore) Initialize() {
+	var err error
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.pool, err = pgxpool.New(p.ctx, config.Current.Store.Postgres.Uri)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not create postgres-store")
+		return
+	}
+
+	if err := p.pool.Ping(p.ctx); err != nil {
+		log.Fatal().Err(err).Msg("Could not reach PostgreSQL")
+		return
+	}
+
+	p.connected.Store(true)
+	log.Info().Msg("PostgreSQL connection established")
+
+	go p.watchConnection()
+}
+
+func (p *PostgresStore) InitializeResource(dataSource reconciliation.DataSource, resourceConfig *config.Resource) {
+	_ = dataSource
+	table := resourceConfig.GetGroupVersionName()
+	resource := resourceConfig.GetGroupVersionResource()
+
+	if err := p.ensureTable(p.ctx, table); err != nil {
+		log.Warn().Fields(utils.CreateFieldForResource(&resource)).Err(err).Msg("Could not create table in PostgreSQL")
+	}
+
+	for _, index := range resourceConfig.PostgresIndexes {
+		statement, err := p.createIndexStatement(table, &index)
+		if err == nil {
+			_, err = p.pool.Exec(p.ctx, statement)
+		}
+
+		if err != nil {
+			log.Warn().Fields(utils.CreateFieldForResource(&resource)).Err(err).Msg("Could not create index in PostgreSQL")
+		}
+	}
+
+	go p.collectMetrics(table)
+}
+
+func (p *PostgresStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	tableName := utils.GetGroupVersionId(obj)
+
+	if err := p.put(ctx, obj, obj); err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(tableName, "create", obj)).
+			Msg("Failed to create or update document in PostgreSQL")
+		return err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(tableName, "create", obj)).
+		Msg("Resource created or updated in PostgreSQL")
+	return nil
+}
+
+func (p *PostgresStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	tableName := utils.GetGroupVersionId(oldObj)
+
+	if err := p.put(ctx, oldObj, newObj); err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(tableName, "update", oldObj)).
+			Msg("Failed to update document in PostgreSQL")
+		return err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(tableName, "update", oldObj)).
+		Msg("Resource updated in PostgreSQL")
+	return nil
+}
+
+func (p *PostgresStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	tableName := utils.GetGroupVersionId(obj)
+
+	id, err := utils.GetMongoId(obj)
+	if err == nil {
+		statement := fmt.Sprintf("DELETE FROM %s WHERE id = $1", p.tableIdentifier(tableName))
+		if _, err = p.pool.Exec(ctx, statement, id); isUndefinedTable(err) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(tableName, "delete", obj)).
+			Msg("Failed to delete document in PostgreSQL")
+		return err
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(tableName, "delete", obj)).
+		Msg("Resource deleted in PostgreSQL")
+	return nil
+}
+
+func (p *PostgresStore) Count(ctx context.Context, tableName string) (int, error) {
+	var count int
+	statement := fmt.Sprintf("SELECT count(*) FROM %s", p.tableIdentifier(tableName))
+	if err := p.pool.QueryRow(ctx, statement).Scan(&count); err != nil {
+		if isUndefinedTable(err) {
+			return 0, nil
+		}
+
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(tableName, "count", nil)).
+			Msg("Failed to count documents in PostgreSQL")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *PostgresStore) Keys(ctx context.Context, tableName string) ([]string, error) {
+	statement := fmt.Sprintf("SELECT id FROM %s ORDER BY id", p.tableIdentifier(tableName))
+	rows, err := p.pool.Query(ctx, statement)
+	if err == nil {
+		var keys []string
+		if keys, err = pgx.CollectRows(rows, pgx.RowTo[string]); err == nil {
+			return keys, nil
+		}
+	}
+
+	if isUndefinedTable(err) {
+		return []string{}, nil
+	}
+
+	log.Error().Err(err).
+		Fields(utils.CreateFieldsForCollection(tableName, "keys", nil)).
+		Msg("Failed to get keys from PostgreSQL")
+	return nil, err
+}
+
+func (p *PostgresStore) Read(ctx context.Context, tableName string, key string) (*unstructured.Unstructured, error) {
+	var data []byte
+	statement := fmt.Sprintf("SELECT data FROM %s WHERE id = $1", p.tableIdentifier(tableName))
+	if err := p.pool.QueryRow(ctx, statement, key).Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isUndefinedTable(err) {
+			return nil, ErrResourceNotFound
+		}
+
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(tableName, "read", nil)).
+			Str("key", key).
+			Msg("Failed to read resource from PostgreSQL")
+		return nil, err
+	}
+
+	var result unstructured.Unstructured
+	if err := result.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (p *PostgresStore) List(ctx context.Context, tableName string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	filter, args := createPostgresFilter(fieldSelector)
+
+	statement := fmt.Sprintf("SELECT data FROM %s%s ORDER BY id", p.tableIdentifier(tableName), filter)
+	if limit > 0 {
+		args = append(args, limit)
+		statement += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := p.pool.Query(ctx, statement, args...)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return nil, nil
+		}
+
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollectionWithListOptions(tableName, "list", nil, limit, fieldSelector)).
+			Msg("Failed to list resources from PostgreSQL")
+		return nil, err
+	}
+
+	documents, err := pgx.CollectRows(rows, pgx.RowTo[[]byte])
+	if err != nil {
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollectionWithListOptions(tableName, "list", nil, limit, fieldSelector)).
+			Msg("Failed to read rows while listing resources from PostgreSQL")
+		return nil, err
+	}
+
+	results := make([]unstructured.Unstructured, 0, len(documents))
+	for _, data := range documents {
+		var resource unstructured.Unstructured
+		if err := resource.UnmarshalJSON(data); err != nil {
+			log.Error().Err(err).
+				Fields(utils.CreateFieldsForCollectionWithListOptions(tableName, "list", nil, limit, fieldSelector)).
+				Msg("Failed to decode resource from PostgreSQL")
+			continue
+		}
+		results = append(results, resource)
+	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCollectionWithListOptions(tableName, "list", nil, limit, fieldSelector)).
+		Int("count", len(results)).
+		Msg("Resources listed from PostgreSQL")
+
+	return results, nil
+}
+
+func (p *PostgresStore) Shutdown() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	if p.pool != nil {
+		p.pool.Close()
+	}
+	p.connected.Store(false)
+}
+
+func (p *PostgresStore) Connected() bool {
+	return p.connected.Load()
+}
+
+func (p *PostgresStore) put(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	tableName := utils.GetGroupVersionId(oldObj)
+
+	oldId, err := utils.GetMongoId(oldObj)
+	if err != nil {
+		return err
+	}
+
+	newId, err := utils.GetMongoId(newObj)
+	if err != nil {
+		return err
+	}
+
+	data, err := newObj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	if err := p.ensureTable(ctx, tableName); err != nil {
+		return err
+	}
+
+	table := p.tableIdentifier(tableName)
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		if oldId != newId {
+			if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", table), oldId); err != nil {
+				return err
+			}
+		}
+
+		statement := fmt.Sprintf(`INSERT INTO %s (id, data, updated_at) VALUES ($1, $2, now())
+			ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at`, table)
+		_, err := tx.Exec(ctx, statement, newId, data)
+		return err
+	})
+}
+
+// ensureTable creates the table of a dataset unless it has already been created by this instance.
+func (p *PostgresStore) ensureTable(ctx context.Context, tableName string) error {
+	if _, ok := p.tables.Load(tableName); ok {
+		return nil
+	}
+
+	statement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id text PRIMARY KEY,
+		data jsonb NOT NULL,
+		updated_at timestamptz NOT NULL DEFAULT now()
+	)`, p.tableIdentifier(tableName))
+	if _, err := p.pool.Exec(ctx, statement); err != nil {
+		return err
+	}
+
+	p.tables.Store(tableName, struct{}{})
+	return nil
+}
+
+func (p *PostgresStore) createIndexStatement(tableName string, index *config.PostgresResourceIndex) (string, error) {
+	if len(index.Fields) == 0 {
+		return "", ErrEmptyPostgresIndex
+	}
+
+	name := index.Name
+	if name == "" {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(tableName + "/" + strings.Join(index.Fields, ",")))
+		name = fmt.Sprintf("quasar_idx_%x", hash.Sum64())
+	}
+
+	expressions := make([]string, 0, len(index.Fields))
+	for _, field := range index.Fields {
+		expressions = append(expressions, postgresFieldExpression(field))
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s)",
+		unique, pgx.Identifier{name}.Sanitize(), p.tableIdentifier(tableName), strings.Join(expressions, ", ")), nil
+}
+
+func (p *PostgresStore) tableIdentifier(tableName string) string {
+	if schema := config.Current.Store.Postgres.Schema; schema != "" {
+		return pgx.Identifier{schema, tableName}.Sanitize()
+	}
+	return pgx.Identifier{tableName}.Sanitize()
+}
+
+func (p *PostgresStore) watchConnection() {
+	ticker := time.NewTicker(postgresHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.pool.Ping(p.ctx); err != nil {
+				log.Debug().Err(err).Msg("PostgreSQL health check failed")
+				p.onDisconnected()
+				continue
+			}
+			p.onConnected()
+
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *PostgresStore) onConnected() {
+	if !p.connected.CompareAndSwap(false, true) {
+		return
+	}
+
+	metrics.GetOrCreateCustomCounter("postgres_reconnect_total").
+		WithLabelValues().
+		Inc()
+	log.Info().Msg("PostgreSQL connection re-established")
+}
+
+func (p *PostgresStore) onDisconnected() {
+	if !p.connected.CompareAndSwap(true, false) {
+		return
+	}
+
+	metrics.GetOrCreateCustomCounter("postgres_disconnect_total").
+		WithLabelValues().
+		Inc()
+	log.Warn().Msg("PostgreSQL connection lost")
+}
+
+func (p *PostgresStore) collectMetrics(tableName string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error().Msgf("Recovered from %v during postgres metric collection", err)
+		}
+	}()
+
+	for p.ctx.Err() == nil {
+		count, err := p.Count(p.ctx, tableName)
+		if err != nil {
+			log.Error().Err(err).Fields(map[string]any{
+				"table": tableName,
+			}).Msg("Could not count documents in PostgreSQL")
+
+			time.Sleep(15 * time.Second)
+			continue
+		}
+
+		metrics.GetOrCreateCustom(tableName + "_postgres_count").WithLabelValues().Set(float64(count))
+		time.Sleep(15 * time.Second)
+	}
+}
+
+// createPostgresFilter translates a field selector into a WHERE clause with positional arguments.
+// Fields are sorted so that equal selectors always produce the same statement.
+func createPostgresFilter(fieldSelector string) (string, []any) {
+	fields := utils.ParseFieldSelector(fieldSelector)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	args := make([]any, 0, len(fields))
+	conditions := make([]string, 0, len(fields))
+	for _, path := range slices.Sorted(maps.Keys(fields)) {
+		args = append(args, fields[path])
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", postgresFieldExpression(path), len(args)))
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// postgresFieldExpression returns the expression extracting the given dot-separated path from the document as text.
+// The path is inlined as an escaped literal (instead of being passed as an argument) so that the planner can match
+// the expression against the indexes created from postgresIndexes.
+func postgresFieldExpression(path string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	segments := strings.Split(strings.TrimPrefix(path, "."), ".")
+	for i, segment := range segments {
+		segments[i] = `"` + escaper.Replace(segment) + `"`
+	}
+
+	literal := "{" + strings.Join(segments, ",") + "}"
+	return "(data #>> '" + strings.ReplaceAll(literal, "'", "''") + "')"
+}
+
+func isUndefinedTable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == postgresUndefinedTable
+}
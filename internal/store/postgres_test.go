@@ -0,0 +1,133 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+)
+
+var postgresStore *PostgresStore
+
+func setupPostgresStore() *PostgresStore {
+	if postgresStore == nil {
+		postgresStore = new(PostgresStore)
+		postgresStore.Initialize()
+
+		resourceConfig := config.Resource{}
+		resourceConfig.Kubernetes.Group = "subscriber.horizon.telekom.de"
+		resourceConfig.Kubernetes.Version = "v1"
+		resourceConfig.Kubernetes.Resource = "subscriptions"
+		resourceConfig.PostgresIndexes = []config.PostgresResourceIndex{
+			{Fields: []string{"spec.subscription.subscriptionId"}},
+		}
+		postgresStore.InitializeResource(nil, &resourceConfig)
+	}
+	return postgresStore
+}
+
+func TestPostgresStore_CRUD(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupPostgresStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	for _, subscription := range subscriptions {
+		assertions.NoError(store.Create(context.Background(), subscription))
+	}
+
+	count, err := store.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count)
+
+	keys, err := store.Keys(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.ElementsMatch([]string{string(subscriptions[0].GetUID()), string(subscriptions[1].GetUID())}, keys)
+
+	updated := subscriptions[0].DeepCopy()
+	updated.SetLabels(map[string]string{"postgres_test": "true"})
+	assertions.NoError(store.Update(context.Background(), subscriptions[0], updated))
+
+	obj, err := store.Read(context.Background(), dataset, string(subscriptions[0].GetUID()))
+	assertions.NoError(err)
+	assertions.Equal("true", obj.GetLabels()["postgres_test"])
+
+	assertions.NoError(store.Delete(context.Background(), subscriptions[0]))
+	_, err = store.Read(context.Background(), dataset, string(subscriptions[0].GetUID()))
+	assertions.ErrorIs(err, ErrResourceNotFound)
+
+	count, _ = store.Count(context.Background(), dataset)
+	assertions.Equal(len(subscriptions)-1, count)
+
+	assertions.NoError(store.Delete(context.Background(), subscriptions[1]))
+}
+
+func TestPostgresStore_List(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupPostgresStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+	for _, subscription := range subscriptions {
+		assertions.NoError(store.Create(context.Background(), subscription))
+	}
+
+	items, err := store.List(context.Background(), dataset, "", 0)
+	assertions.NoError(err)
+	assertions.Len(items, len(subscriptions))
+
+	items, err = store.List(context.Background(), dataset, "", 1)
+	assertions.NoError(err)
+	assertions.Len(items, 1, "limit should be respected")
+
+	subscriptionId := subscriptions[1].GetName()
+	items, err = store.List(context.Background(), dataset, "spec.subscription.subscriptionId="+subscriptionId+",metadata.namespace=playground", 0)
+	assertions.NoError(err)
+	if assertions.Len(items, 1, "field selector should match exactly one item") {
+		assertions.Equal(subscriptionId, items[0].GetName())
+	}
+
+	items, err = store.List(context.Background(), dataset, "metadata.name=does-not-exist", 0)
+	assertions.NoError(err)
+	assertions.Empty(items)
+}
+
+func TestPostgresStore_UnknownDataset(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupPostgresStore()
+
+	count, err := store.Count(context.Background(), "unknown.dataset.v1")
+	assertions.NoError(err)
+	assertions.Equal(0, count, "unknown datasets should be empty")
+
+	_, err = store.Read(context.Background(), "unknown.dataset.v1", "foo")
+	assertions.ErrorIs(err, ErrResourceNotFound)
+}
+
+func TestCreatePostgresFilter(t *testing.T) {
+	assertions := assert.New(t)
+
+	filter, args := createPostgresFilter("")
+	assertions.Empty(filter)
+	assertions.Empty(args)
+
+	filter, args = createPostgresFilter("spec.environment=playground, metadata.name=foo")
+	assertions.Equal(` WHERE (data #>> '{"metadata","name"}') = $1 AND (data #>> '{"spec","environment"}') = $2`, filter)
+	assertions.Equal([]any{"foo", "playground"}, args)
+
+	assertions.Equal(`(data #>> '{"it''s","a\"b"}')`, postgresFieldExpression(`it's.a"b`), "paths must be escaped")
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/telekom/quasar/internal/config"
+	reconciler "github.com/telekom/quasar/internal/reconciliation"
+)
+
+// reconnectPollInterval is the interval in which the connection of stores that do not notify about reconnects
+// themselves is checked.
+const reconnectPollInterval = 5 * time.Second
+
+// storeReconciliation is the reconciliation of a dataset in one of the managed stores.
+type storeReconciliation struct {
+	store          *ManagedStore
+	reconciliation *reconciler.Reconciliation
+}
+
+// Reconciler is implemented by store managers that reconcile their stores with a data source.
+type Reconciler interface {
+	// DiffReconciliation reports for each reconciled store the differences a reconciliation of the resource would
+	// resolve without resolving them.
+	DiffReconciliation(ctx context.Context, dataSource reconciler.DataSource, resourceConfig *config.Resource) ([]*reconciler.Report, error)
+	// Reconcile reconciles every reconciled store with the data source and returns the result for each of them.
+	Reconcile(ctx context.Context, dataSource reconciler.DataSource, resourceConfig *config.Resource) ([]*reconciler.Result, error)
+}
+
+// LeaderReconciler is implemented by store managers whose periodic reconciliation can be bound to a context, so that
+// only the leader of several instances reconciles the stores.
+type LeaderReconciler interface {
+	Reconciler
+	// InitializeStores initializes the resource in all stores without starting the periodic reconciliation.
+	InitializeStores(dataSource reconciler.DataSource, resourceConfig *config.Resource)
+	// StartReconciliation starts the periodic reconciliation of the resource in every reconciled store. The stores
+	// are not reconciled anymore, neither periodically nor after reconnects, once the context is done.
+	StartReconciliation(ctx context.Context, dataSource reconciler.DataSource, resourceConfig *config.Resource)
+}
+
+// StartReconciliation starts the periodic reconciliation of the resource for every store that is reconciled
+// until the context is done. The reconciliations replace the ones that have been started for the resource before.
+func (m *StoreManager) StartReconciliation(ctx context.Context, dataSource reconciler.DataSource, resourceConfig *config.Resource) {
+	dataset := resourceConfig.GetGroupVersionName()
+	reconciliations := m.newReconciliations(dataSource, resourceConfig)
+
+	m.mu.Lock()
+	m.reconciliations[dataset] = reconciliations
+	m.mu.Unlock()
+
+	for _, storeRecon := range reconciliations {
+		// start reconcile immediately for provisioning mode to ensure initial store filling
+		if config.Current.Mode == config.ModeProvisioning && storeRecon.store.Connected() {
+			storeRecon.reconciliation.SafeReconcile(ctx, storeRecon.store)
+		}
+
+		go storeRecon.reconciliation.StartPeriodicReconcile(ctx, reconciliationInterval(storeRecon.store.Type), storeRecon.store)
+	}
+
+	// keep reconnects from reconciling the stores once the context is done
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if slices.Equal(m.reconciliations[dataset], reconciliations) {
+			delete(m.reconciliations, dataset)
+		}
+	}()
+}
+
+// DiffReconciliation compares every reconciled store with the data source without writing anything.
+// Errors of single stores are joined and do not stop the comparison of the remaining stores.
+func (m *StoreManager) DiffReconciliation(
+	ctx context.Context,
+	dataSource reconciler.DataSource,
+	resourceConfig *config.Resource,
+) ([]*reconciler.Report, error) {
+	reports := make([]*reconciler.Report, 0)
+	var errs []error
+
+	for _, storeRecon := range m.newReconciliations(dataSource, resourceConfig) {
+		report, err := storeRecon.reconciliation.DryRun(ctx, storeRecon.store)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("store %q: %w", storeRecon.store.Type, err))
+			continue
+		}
+		report.Store = storeRecon.store.Type
+		reports = append(reports, report)
+	}
+
+	return reports, errors.Join(errs...)
+}
+
+// Reconcile reconciles every reconciled store with the data source right away. If the resource has been initialized,
+// its running reconciliations are used, so that they do not run concurrently with the periodic reconciliation, and
+// the given data source is ignored. Errors of single stores are joined and do not stop the remaining reconciliations.
+func (m *StoreManager) Reconcile(
+	ctx context.Context,
+	dataSource reconciler.DataSource,
+	resourceConfig *config.Resource,
+) ([]*reconciler.Result, error) {
+	reconciliations := m.getDatasetReconciliations(resourceConfig.GetGroupVersionName())
+	if len(reconciliations) == 0 {
+		reconciliations = m.newReconciliations(dataSource, resourceConfig)
+	}
+
+	results := make([]*reconciler.Result, 0, len(reconciliations))
+	var errs []error
+
+	for _, storeRecon := range reconciliations {
+		result, err := storeRecon.reconciliation.SafeReconcile(ctx, storeRecon.store)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("store %q: %w", storeRecon.store.Type, err))
+			continue
+		}
+		result.Store = storeRecon.store.Type
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// newReconciliations creates a reconciliation of the resource for every store that can be read from. If the data
+// source lists the resources from the manager itself, as the provisioning API does, the authoritative store is the
+// source of truth and only the replicas are reconciled against it.
+func (m *StoreManager) newReconciliations(dataSource reconciler.DataSource, resourceConfig *config.Resource) []*storeReconciliation {
+	selfSourced := m.isDataSource(dataSource)
+
+	reconciliations := make([]*storeReconciliation, 0, len(m.stores))
+	for _, store := range m.stores {
+		if store.Role == config.StoreRoleSink {
+			continue
+		}
+
+		source := dataSource
+		if selfSourced {
+			if store == m.primary {
+				continue
+			}
+			source = reconciler.NewDataSourceFromStore(m.primary, *resourceConfig)
+		}
+
+		reconciliations = append(reconciliations, &storeReconciliation{
+			store:          store,
+			reconciliation: reconciler.NewReconciliation(source, resourceConfig, reconcileMode(store.Type)),
+		})
+	}
+	return reconciliations
+}
+
+// isDataSource returns whether the data source lists the resources from the manager itself.
+func (m *StoreManager) isDataSource(dataSource reconciler.DataSource) bool {
+	storeSource, ok := dataSource.(*reconciler.StoreDataSource)
+	return ok && storeSource.Store() == m
+}
+
+// watchReconnects reconciles all datasets of the store once it has reconnected.
+// Stores that do not notify about reconnects themselves are checked periodically until the manager is shut down.
+func (m *StoreManager) watchReconnects(store *ManagedStore) {
+	if notifier, ok := unwrapStore(store.Store).(reconnectNotifier); ok {
+		notifier.OnReconnect(func() {
+			m.reconcileStore(store)
+		})
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(reconnectPollInterval)
+		defer ticker.Stop()
+
+		connected := store.Connected()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+
+			case <-ticker.C:
+				wasConnected := connected
+				connected = store.Connected()
+				if connected && !wasConnected {
+					m.logger.Info().Str("storeType", store.Type).Msg("Store reconnected, starting reconciliation")
+					m.reconcileStore(store)
+				}
+			}
+		}
+	}()
+}
+
+// reconcileStore reconciles all datasets of the store.
+func (m *StoreManager) reconcileStore(store *ManagedStore) {
+	for _, reconciliation := range m.getReconciliations(store) {
+		reconciliation.SafeReconcile(m.ctx, store)
+	}
+}
+
+// getDatasetReconciliations returns the reconciliations of the dataset in all stores.
+func (m *StoreManager) getDatasetReconciliations(dataset string) []*storeReconciliation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.reconciliations[dataset])
+}
+
+// getReconciliations returns the reconciliations of all datasets of the store.
+func (m *StoreManager) getReconciliations(store *ManagedStore) []*reconciler.Reconciliation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	reconciliations := make([]*reconciler.Reconciliation, 0, len(m.reconciliations))
+	for _, dataset := range m.reconciliations {
+		for _, reconciliation := range dataset {
+			if reconciliation.store == store {
+				reconciliations = append(reconciliations, reconciliation.reconciliation)
+			}
+		}
+	}
+	return reconciliations
+}
@@ -6,95 +6,369 @@ package store
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"github.com/telekom/quasar/internal/config"
-	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/metrics"
+	reconciler "github.com/telekom/quasar/internal/reconciliation"
 	"github.com/telekom/quasar/internal/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+const (
+	redisHealthCheckInterval = 5 * time.Second
+	redisListBatchSize       = 500
+)
+
+// RedisStore keeps every resource as a json string under "<dataset>:<name>" and tracks the names
+// of a dataset in a set stored under "<dataset>". In cluster mode the dataset is wrapped in a hash tag
+// ("{<dataset>}") so that all keys of a dataset share a slot and can be written transactionally.
 type RedisStore struct {
-	client *redis.Client
-	ctx    context.Context
+	reconnectCallbacks
+	client    redis.UniversalClient
+	mode      config.RedisMode
+	ctx       context.Context
+	cancel    context.CancelFunc
+	connected atomic.Bool
 }
 
 func (s *RedisStore) Initialize() {
-	s.ctx = context.Background()
-	s.client = redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", config.Current.Store.Redis.Host, config.Current.Store.Redis.Port),
-		Username: config.Current.Store.Redis.Username,
-		Password: config.Current.Store.Redis.Password,
-		DB:       config.Current.Store.Redis.Database,
-	})
+	var err error
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.mode = config.Current.Store.Redis.Mode
+	s.client, err = createRedisClient(&config.Current.Store.Redis)
+	if err != nil {
+		log.Fatal().Err(err).Str("mode", string(s.mode)).Msg("Could not create redis client!")
+	}
 
-	log.Debug().Msg("Trying to reach redis...")
+	log.Debug().Str("mode", string(s.mode)).Msg("Trying to reach redis...")
 	status := s.client.Ping(s.ctx)
 	if err := status.Err(); err != nil {
 		log.Fatal().Err(err).Msg("Could not reach redis!")
 	}
 
+	s.connected.Store(true)
 	log.Info().Msg("Redis connection established...")
+
+	go s.watchConnection()
 }
 
-func (s *RedisStore) InitializeResource(dataSource reconciliation.DataSource, resourceConfig *config.Resource) {
-	_, _ = dataSource, resourceConfig
-	// Nothing to do here
+func (s *RedisStore) InitializeResource(_ reconciler.DataSource, resourceConfig *config.Resource) {
+	go s.collectMetrics(resourceConfig.GetGroupVersionName())
 }
 
-func (s *RedisStore) Create(obj *unstructured.Unstructured) error {
-	status := s.client.JSONSet(s.ctx, obj.GetName(), ".", obj.Object)
-	if err := status.Err(); err != nil {
-		log.Error().Fields(utils.GetFieldsOfObject(obj)).Err(err).Msg("Could not write resource to store!")
+func (s *RedisStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	if err := s.write(ctx, obj); err != nil {
+		log.Error().
+			Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "create", obj)).
+			Err(err).
+			Msg("Could not write resource to store!")
 		return err
 	}
+
+	log.Debug().
+		Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "create", obj)).
+		Msg("Resource created or updated in Redis")
 	return nil
 }
 
-func (s *RedisStore) Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
-	status := s.client.JSONSet(s.ctx, oldObj.GetName(), ".", newObj)
-	if err := status.Err(); err != nil {
-		log.Error().Fields(utils.GetFieldsOfObject(newObj)).Err(err).Msg("Could not update resource in store!")
+func (s *RedisStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	if oldObj.GetName() != newObj.GetName() {
+		if err := s.remove(ctx, oldObj); err != nil {
+			log.Error().
+				Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(oldObj), "update", oldObj)).
+				Err(err).
+				Msg("Could not remove renamed resource from store!")
+			return err
+		}
+	}
+
+	if err := s.write(ctx, newObj); err != nil {
+		log.Error().
+			Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(newObj), "update", newObj)).
+			Err(err).
+			Msg("Could not update resource in store!")
 		return err
 	}
+
+	log.Debug().Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(newObj), "update", newObj)).Msg("Resource updated in Redis")
 	return nil
 }
 
-func (s *RedisStore) Delete(obj *unstructured.Unstructured) error {
-	status := s.client.JSONDel(s.ctx, obj.GetName(), ".")
-	if err := status.Err(); err != nil {
-		log.Error().Fields(utils.GetFieldsOfObject(obj)).Err(err).Msg("Could not delete resource from store!")
+func (s *RedisStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	if err := s.remove(ctx, obj); err != nil {
+		log.Error().
+			Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "delete", obj)).
+			Err(err).
+			Msg("Could not delete resource from store!")
 		return err
 	}
+
+	log.Debug().Fields(utils.CreateFieldsForCacheMap(utils.GetGroupVersionId(obj), "delete", obj)).Msg("Resource deleted in Redis")
 	return nil
 }
 
+func (s *RedisStore) Count(ctx context.Context, dataset string) (int, error) {
+	size, err := s.client.SCard(ctx, s.indexKey(dataset)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(size), nil
+}
+
+func (s *RedisStore) Keys(ctx context.Context, dataset string) ([]string, error) {
+	keys, err := s.client.SMembers(ctx, s.indexKey(dataset)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *RedisStore) Read(ctx context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	data, err := s.client.Get(ctx, s.objectKey(dataset, key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	var obj unstructured.Unstructured
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+func (s *RedisStore) List(ctx context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	names, err := s.Keys(ctx, dataset)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	fields := utils.ParseFieldSelector(fieldSelector)
+
+	var result []unstructured.Unstructured
+	for batch := range slices.Chunk(names, redisListBatchSize) {
+		keys := make([]string, 0, len(batch))
+		for _, name := range batch {
+			keys = append(keys, s.objectKey(dataset, name))
+		}
+
+		values, err := s.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				// the entry vanished between reading the index and the values
+				continue
+			}
+
+			var obj unstructured.Unstructured
+			if err := obj.UnmarshalJSON([]byte(data)); err != nil {
+				continue
+			}
+
+			if !utils.MatchFields(&obj, fields) {
+				continue
+			}
+
+			result = append(result, obj)
+			if limit > 0 && int64(len(result)) >= limit {
+				return result, nil
+			}
+		}
+	}
+
+	return result, nil
+}
+
 func (s *RedisStore) Shutdown() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	if s.client != nil {
+		if err := s.client.Close(); err != nil {
+			log.Error().Err(err).Msg("Could not close redis client")
+		}
+	}
+	s.connected.Store(false)
+}
+
+func (s *RedisStore) Connected() bool { return s.connected.Load() }
+
+func (s *RedisStore) write(ctx context.Context, obj *unstructured.Unstructured) error {
+	dataset := utils.GetGroupVersionId(obj)
+
+	json, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.objectKey(dataset, obj.GetName()), json, 0)
+		pipe.SAdd(ctx, s.indexKey(dataset), obj.GetName())
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) remove(ctx context.Context, obj *unstructured.Unstructured) error {
+	dataset := utils.GetGroupVersionId(obj)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.objectKey(dataset, obj.GetName()))
+		pipe.SRem(ctx, s.indexKey(dataset), obj.GetName())
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) indexKey(dataset string) string {
+	if s.mode == config.RedisModeCluster {
+		return "{" + dataset + "}"
+	}
+	return dataset
+}
+
+func (s *RedisStore) objectKey(dataset string, name string) string {
+	return s.indexKey(dataset) + ":" + name
 }
 
-func (s *RedisStore) Count(dataset string) (int, error) {
-	_ = dataset
-	// TODO implement me
-	panic("implement me")
+func createRedisClient(redisConfig *config.Redis) (redis.UniversalClient, error) {
+	var tlsConfig *tls.Config
+	if redisConfig.TLS.Enabled {
+		var err error
+		if tlsConfig, err = redisConfig.TLS.ToTLSConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch redisConfig.Mode {
+	case config.RedisModeStandalone, "":
+		return redis.NewClient(&redis.Options{
+			Addr:      fmt.Sprintf("%s:%d", redisConfig.Host, redisConfig.Port),
+			Username:  redisConfig.Username,
+			Password:  redisConfig.Password,
+			DB:        redisConfig.Database,
+			TLSConfig: tlsConfig,
+		}), nil
+
+	case config.RedisModeSentinel:
+		if redisConfig.Sentinel.MasterName == "" || len(redisConfig.Addresses) == 0 {
+			return nil, ErrIncompleteRedisSentinelConfig
+		}
+
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       redisConfig.Sentinel.MasterName,
+			SentinelAddrs:    redisConfig.Addresses,
+			SentinelUsername: redisConfig.Sentinel.Username,
+			SentinelPassword: redisConfig.Sentinel.Password,
+			Username:         redisConfig.Username,
+			Password:         redisConfig.Password,
+			DB:               redisConfig.Database,
+			TLSConfig:        tlsConfig,
+		}), nil
+
+	case config.RedisModeCluster:
+		if len(redisConfig.Addresses) == 0 {
+			return nil, ErrIncompleteRedisClusterConfig
+		}
+
+		if redisConfig.Database != 0 {
+			log.Warn().Int("database", redisConfig.Database).Msg("Redis cluster does not support databases, ignoring database setting")
+		}
+
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     redisConfig.Addresses,
+			Username:  redisConfig.Username,
+			Password:  redisConfig.Password,
+			TLSConfig: tlsConfig,
+		}), nil
+
+	default:
+		return nil, ErrUnknownRedisMode
+	}
 }
 
-func (s *RedisStore) Keys(dataset string) ([]string, error) {
-	_ = dataset
-	// TODO implement me
-	panic("implement me")
+func (s *RedisStore) watchConnection() {
+	ticker := time.NewTicker(redisHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.client.Ping(s.ctx).Err(); err != nil {
+				log.Debug().Err(err).Msg("Redis health check failed")
+				s.onDisconnected()
+				continue
+			}
+			s.onConnected()
+
+		case <-s.ctx.Done():
+			return
+		}
+	}
 }
 
-func (s *RedisStore) Read(dataset string, key string) (*unstructured.Unstructured, error) {
-	_, _ = dataset, key
-	// TODO implement me
-	panic("implement me")
+func (s *RedisStore) onConnected() {
+	if !s.connected.CompareAndSwap(false, true) {
+		return
+	}
+
+	metrics.GetOrCreateCustomCounter("redis_reconnect_total").
+		WithLabelValues().
+		Inc()
+	log.Info().Msg("Redis connection re-established")
+
+	s.notifyReconnect()
 }
 
-func (s *RedisStore) List(dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
-	_, _, _ = dataset, fieldSelector, limit
-	panic("implement me")
+func (s *RedisStore) onDisconnected() {
+	if !s.connected.CompareAndSwap(true, false) {
+		return
+	}
+
+	metrics.GetOrCreateCustomCounter("redis_disconnect_total").
+		WithLabelValues().
+		Inc()
+	log.Warn().Msg("Redis connection lost")
 }
 
-func (s *RedisStore) Connected() bool { panic("implement me") }
+func (s *RedisStore) collectMetrics(dataset string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error().Msgf("Recovered from %v during redis metric collection", err)
+		}
+	}()
+
+	for {
+		size, err := s.Count(s.ctx, dataset)
+		if err != nil {
+			log.Error().Err(err).Fields(map[string]any{
+				"dataset": dataset,
+			}).Msg("Could not count resources in Redis")
+
+			time.Sleep(15 * time.Second)
+			continue
+		}
+
+		metrics.GetOrCreateCustom(dataset + "_redis_count").WithLabelValues().Set(float64(size))
+		time.Sleep(15 * time.Second)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+)
+
+var redisStore *RedisStore
+
+func setupRedisStore() *RedisStore {
+	if redisStore == nil {
+		redisStore = new(RedisStore)
+		redisStore.Initialize()
+	}
+	return redisStore
+}
+
+func TestRedisStore_Initialize(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	assertions.NotPanics(func() {
+		setupRedisStore()
+	}, "unexpected panic")
+
+	assertions.True(redisStore.Connected(), "store should be connected after initialization")
+	assertions.Equal(0, test.LogRecorder.GetRecordCount(zerolog.ErrorLevel), "Initialize should not produce error logs on success")
+}
+
+func TestRedisStore_CreateAndRead(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupRedisStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	for _, subscription := range subscriptions {
+		assertions.NoError(store.Create(context.Background(), subscription), "could not write subscription %s", subscription.GetName())
+
+		dataset := utils.GetGroupVersionId(subscription)
+		obj, err := store.Read(context.Background(), dataset, subscription.GetName())
+		assertions.NoError(err, "could not read subscription %s", subscription.GetName())
+		assertions.Equal(subscription.GetUID(), obj.GetUID())
+	}
+
+	_, err := store.Read(context.Background(), utils.GetGroupVersionId(subscriptions[0]), "does-not-exist")
+	assertions.ErrorIs(err, ErrResourceNotFound)
+}
+
+func TestRedisStore_Update(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupRedisStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	for _, subscription := range subscriptions {
+		updatedSubscription := subscription.DeepCopy()
+		updatedSubscription.SetLabels(map[string]string{"redis_test": "true"})
+
+		assertions.NoError(store.Update(context.Background(), subscription, updatedSubscription))
+
+		obj, err := store.Read(context.Background(), utils.GetGroupVersionId(subscription), subscription.GetName())
+		assertions.NoError(err)
+		assertions.Equal("true", obj.GetLabels()["redis_test"], "subscription %s was not updated", subscription.GetName())
+	}
+}
+
+func TestRedisStore_CountAndKeys(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupRedisStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	count, err := store.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count)
+
+	keys, err := store.Keys(context.Background(), dataset)
+	assertions.NoError(err)
+	for _, subscription := range subscriptions {
+		assertions.Contains(keys, subscription.GetName())
+	}
+
+	count, err = store.Count(context.Background(), "unknown.dataset.v1")
+	assertions.NoError(err)
+	assertions.Equal(0, count, "unknown datasets should be empty")
+}
+
+func TestRedisStore_List(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupRedisStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	items, err := store.List(context.Background(), dataset, "", 0)
+	assertions.NoError(err)
+	assertions.Len(items, len(subscriptions))
+
+	items, err = store.List(context.Background(), dataset, "", 1)
+	assertions.NoError(err)
+	assertions.Len(items, 1, "limit should be respected")
+
+	items, err = store.List(context.Background(), dataset, "metadata.name="+subscriptions[1].GetName(), 0)
+	assertions.NoError(err)
+	if assertions.Len(items, 1, "field selector should match exactly one item") {
+		assertions.Equal(subscriptions[1].GetName(), items[0].GetName())
+	}
+
+	items, err = store.List(context.Background(), dataset, "metadata.name=does-not-exist", 0)
+	assertions.NoError(err)
+	assertions.Empty(items)
+}
+
+func TestRedisStore_Delete(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupRedisStore()
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	for _, subscription := range subscriptions {
+		assertions.NoError(store.Delete(context.Background(), subscription))
+
+		_, err := store.Read(context.Background(), dataset, subscription.GetName())
+		assertions.ErrorIs(err, ErrResourceNotFound, "subscription %s should have been deleted", subscription.GetName())
+	}
+
+	count, err := store.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(0, count)
+}
+
+func TestRedisStore_OnDisconnected(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := setupRedisStore()
+
+	store.onDisconnected()
+	assertions.False(store.Connected(), "connected should be false after onDisconnected")
+
+	store.onConnected()
+	assertions.True(store.Connected(), "connected should be true after onConnected")
+}
+
+func TestRedisStore_ClusterKeyLayout(t *testing.T) {
+	assertions := assert.New(t)
+	dataset := "subscriptions.subscriber.horizon.telekom.de.v1"
+
+	standalone := &RedisStore{mode: config.RedisModeStandalone}
+	assertions.Equal(dataset, standalone.indexKey(dataset))
+	assertions.Equal(dataset+":foo", standalone.objectKey(dataset, "foo"))
+
+	cluster := &RedisStore{mode: config.RedisModeCluster}
+	assertions.Equal("{"+dataset+"}", cluster.indexKey(dataset))
+	assertions.Equal("{"+dataset+"}:foo", cluster.objectKey(dataset, "foo"))
+}
+
+func TestCreateRedisClient_InvalidConfig(t *testing.T) {
+	assertions := assert.New(t)
+
+	_, err := createRedisClient(&config.Redis{Mode: config.RedisModeSentinel, Addresses: []string{"localhost:26379"}})
+	assertions.ErrorIs(err, ErrIncompleteRedisSentinelConfig, "sentinel mode without master name should fail")
+
+	_, err = createRedisClient(&config.Redis{Mode: config.RedisModeCluster})
+	assertions.ErrorIs(err, ErrIncompleteRedisClusterConfig, "cluster mode without addresses should fail")
+
+	_, err = createRedisClient(&config.Redis{Mode: "unknown"})
+	assertions.ErrorIs(err, ErrUnknownRedisMode)
+
+	_, err = createRedisClient(&config.Redis{
+		Mode: config.RedisModeStandalone,
+		TLS:  config.RedisTLS{Enabled: true, CAFile: "does-not-exist.pem"},
+	})
+	assertions.Error(err, "missing ca file should fail")
+
+	client, err := createRedisClient(&config.Redis{
+		Mode:      config.RedisModeSentinel,
+		Addresses: []string{"localhost:26379"},
+		Sentinel:  config.RedisSentinel{MasterName: "mymaster"},
+	})
+	assertions.NoError(err)
+	assertions.NoError(client.Close())
+}
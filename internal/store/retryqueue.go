@@ -0,0 +1,473 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/utils"
+	bolt "go.etcd.io/bbolt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type writeOperation string
+
+const (
+	writeOperationCreate writeOperation = "Create"
+	writeOperationUpdate writeOperation = "Update"
+	writeOperationDelete writeOperation = "Delete"
+)
+
+const retryQueueOpenTimeout = 10 * time.Second
+
+var retryQueueBucket = []byte("writes")
+
+// queuedWrite is a write that is waiting to be applied by a RetryQueue.
+type queuedWrite struct {
+	Sequence  uint64         `json:"sequence"`
+	Operation writeOperation `json:"operation"`
+	Key       string         `json:"key"`
+	OldObject map[string]any `json:"oldObject,omitempty"`
+	NewObject map[string]any `json:"newObject,omitempty"`
+	Enqueued  time.Time      `json:"enqueued"`
+	Attempts  int            `json:"-"`
+
+	// ctx is the context the write is applied with, which is only done for writes bound by BindAsyncWrites.
+	ctx context.Context
+}
+
+// RetryQueue applies writes to a store in the background and retries failed writes with exponential backoff.
+// Writes of the same resource are applied in the order they have been enqueued, writes of different resources
+// concurrently. The queue is bounded and can be persisted to disk, so that pending writes survive restarts.
+type RetryQueue struct {
+	store  Store
+	config config.RetryQueue
+	db     *bolt.DB
+	logger zerolog.Logger
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	pending  map[string][]*queuedWrite
+	ready    []string
+	size     int
+	sequence uint64
+	reserved int
+	closed   bool
+	stopped  bool
+	idle     chan struct{}
+	wg       sync.WaitGroup
+
+	depth   prometheus.Gauge
+	dropped prometheus.Counter
+	retries prometheus.Counter
+}
+
+func newRetryQueue(managerId string, storeType string, store Store, queueConfig *config.RetryQueue) (*RetryQueue, error) {
+	if err := validateRetryQueueConfig(queueConfig); err != nil {
+		return nil, err
+	}
+
+	queue := &RetryQueue{
+		store:   store,
+		config:  *queueConfig,
+		pending: make(map[string][]*queuedWrite),
+		logger: log.With().
+			Str("component", "RetryQueue").
+			Str("id", managerId).
+			Str("storeType", storeType).
+			Logger(),
+		depth:   metrics.GetOrCreateCustom(storeType+"_retry_queue_depth", "manager").WithLabelValues(managerId),
+		dropped: metrics.GetOrCreateCustomCounter(storeType+"_retry_queue_dropped_total", "manager").WithLabelValues(managerId),
+		retries: metrics.GetOrCreateCustomCounter(storeType+"_retry_queue_retries_total", "manager").WithLabelValues(managerId),
+	}
+	queue.cond = sync.NewCond(&queue.mu)
+
+	if queueConfig.Path != "" {
+		if err := queue.restore(filepath.Join(queueConfig.Path, managerId+"-"+storeType+".db")); err != nil {
+			return nil, err
+		}
+	}
+
+	return queue, nil
+}
+
+// validateRetryQueueConfig rejects settings with which failed writes would be retried without any delay or pending
+// writes would not be drained on shutdown.
+func validateRetryQueueConfig(queueConfig *config.RetryQueue) error {
+	switch {
+	case queueConfig.InitialBackoff <= 0:
+		return fmt.Errorf("%w: initialBackoff must be positive", ErrInvalidRetryQueueConfig)
+	case queueConfig.Multiplier < 1:
+		return fmt.Errorf("%w: multiplier must be at least 1", ErrInvalidRetryQueueConfig)
+	case queueConfig.DrainTimeout <= 0:
+		return fmt.Errorf("%w: drainTimeout must be positive", ErrInvalidRetryQueueConfig)
+	}
+	return nil
+}
+
+// Start starts the workers of the queue, which immediately begin applying restored writes.
+func (q *RetryQueue) Start() {
+	workers := max(q.config.Workers, 1)
+	q.wg.Add(workers)
+	for range workers {
+		go q.work()
+	}
+}
+
+// Enqueue adds a write to the queue. The write is dropped if the queue is full or already shut down.
+// The objects are copied, so that callers may keep modifying them while the write is pending.
+// The write is persisted without holding the lock of the queue, so that concurrent writes share the disk syncs.
+// Writes with a context bound by BindAsyncWrites are dropped once the context is done and are not persisted.
+func (q *RetryQueue) Enqueue(
+	ctx context.Context,
+	operation writeOperation,
+	oldObj *unstructured.Unstructured,
+	newObj *unstructured.Unstructured,
+) {
+	obj := newObj
+	if obj == nil {
+		obj = oldObj
+	}
+
+	write := &queuedWrite{
+		Operation: operation,
+		Key:       utils.GetGroupVersionId(obj) + "/" + namespacedName(obj),
+		Enqueued:  time.Now(),
+		ctx:       context.Background(),
+	}
+	if asyncWritesBound(ctx) {
+		write.ctx = ctx
+	}
+	if oldObj != nil {
+		write.OldObject = oldObj.DeepCopy().Object
+	}
+	if newObj != nil {
+		write.NewObject = newObj.DeepCopy().Object
+	}
+
+	q.mu.Lock()
+	if size, closed := q.size+q.reserved, q.closed; closed || (q.config.Capacity > 0 && size >= q.config.Capacity) {
+		q.mu.Unlock()
+		q.dropped.Inc()
+		q.logger.Error().
+			Str("operation", string(operation)).
+			Str("key", write.Key).
+			Int("size", size).
+			Bool("closed", closed).
+			Msg("Retry queue is full or closed, dropping write")
+		return
+	}
+
+	q.sequence++
+	write.Sequence = q.sequence
+	q.reserved++
+	q.mu.Unlock()
+
+	if err := q.persist(write); err != nil {
+		q.logger.Warn().Err(err).Str("key", write.Key).Msg("Could not persist write, it will be lost on restart")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved--
+	q.push(write)
+}
+
+// Size returns the number of writes that have not been applied yet.
+func (q *RetryQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.size
+}
+
+// Lag returns how long the oldest pending write has been waiting, which is how far the store is behind at most.
+func (q *RetryQueue) Lag() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var oldest time.Time
+	for _, writes := range q.pending {
+		if enqueued := writes[0].Enqueued; oldest.IsZero() || enqueued.Before(oldest) {
+			oldest = enqueued
+		}
+	}
+
+	if oldest.IsZero() {
+		return 0
+	}
+	return time.Since(oldest)
+}
+
+// Shutdown stops accepting writes, waits until all pending writes have been applied or the drain timeout is reached
+// and stops the workers. Writes that could not be applied in time are kept on disk if the queue is persisted.
+func (q *RetryQueue) Shutdown() {
+	q.mu.Lock()
+	q.closed = true
+	if q.size+q.reserved > 0 && q.idle == nil {
+		q.idle = make(chan struct{})
+	}
+	idle := q.idle
+	q.mu.Unlock()
+
+	if idle != nil {
+		select {
+		case <-idle:
+		case <-time.After(q.config.DrainTimeout):
+		}
+	}
+
+	q.mu.Lock()
+	q.stopped = true
+	remaining := q.size
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	q.wg.Wait()
+
+	if q.db != nil {
+		if err := q.db.Close(); err != nil {
+			q.logger.Error().Err(err).Msg("Could not close retry queue database")
+		}
+	}
+
+	if remaining > 0 {
+		q.logger.Warn().Int("remaining", remaining).Bool("persisted", q.db != nil).
+			Msg("Retry queue could not be drained before shutdown")
+	}
+}
+
+func (q *RetryQueue) work() {
+	defer q.wg.Done()
+
+	for {
+		q.mu.Lock()
+		for len(q.ready) == 0 && !q.stopped {
+			q.cond.Wait()
+		}
+		if q.stopped {
+			q.mu.Unlock()
+			return
+		}
+
+		key := q.ready[0]
+		q.ready = q.ready[1:]
+		write := q.pending[key][0]
+		q.mu.Unlock()
+
+		var err error
+		if write.ctx.Err() == nil {
+			err = q.apply(write)
+		}
+		completed := true
+
+		q.mu.Lock()
+		switch {
+		case write.ctx.Err() != nil:
+			q.logger.Debug().
+				Str("operation", string(write.Operation)).
+				Str("key", write.Key).
+				Msg("Context of write is done, dropping it")
+			q.complete(write)
+
+		case err == nil:
+			q.complete(write)
+
+		case q.config.MaxAttempts > 0 && write.Attempts+1 >= q.config.MaxAttempts:
+			q.dropped.Inc()
+			q.logger.Error().Err(err).
+				Str("operation", string(write.Operation)).
+				Str("key", write.Key).
+				Int("attempts", write.Attempts+1).
+				Msg("Write failed too often, dropping it")
+			q.complete(write)
+
+		default:
+			completed = false
+			write.Attempts++
+			backoff := q.backoff(write.Attempts)
+			q.retries.Inc()
+			q.logger.Warn().Err(err).
+				Str("operation", string(write.Operation)).
+				Str("key", write.Key).
+				Int("attempt", write.Attempts).
+				Dur("backoff", backoff).
+				Msg("Write failed, retrying")
+			time.AfterFunc(backoff, func() {
+				q.mu.Lock()
+				defer q.mu.Unlock()
+
+				q.ready = append(q.ready, write.Key)
+				q.cond.Signal()
+			})
+		}
+		q.mu.Unlock()
+
+		if completed {
+			if err := q.unpersist(write); err != nil {
+				q.logger.Warn().Err(err).Str("key", write.Key).Msg("Could not remove persisted write")
+			}
+		}
+	}
+}
+
+func (q *RetryQueue) apply(write *queuedWrite) error {
+	var oldObj, newObj *unstructured.Unstructured
+	if write.OldObject != nil {
+		oldObj = &unstructured.Unstructured{Object: write.OldObject}
+	}
+	if write.NewObject != nil {
+		newObj = &unstructured.Unstructured{Object: write.NewObject}
+	}
+
+	return applyWrite(write.ctx, q.store, write.Operation, oldObj, newObj)
+}
+
+// push appends the write to the writes of its key. The key only becomes ready if no other write of it is pending,
+// otherwise it becomes ready once the previous write has been completed.
+// The caller must hold the lock.
+func (q *RetryQueue) push(write *queuedWrite) {
+	writes := q.pending[write.Key]
+	q.pending[write.Key] = append(writes, write)
+	q.size++
+	q.depth.Set(float64(q.size))
+
+	if len(writes) == 0 {
+		q.ready = append(q.ready, write.Key)
+		q.cond.Signal()
+	}
+}
+
+// complete removes the write from the queue and makes the next write of its key ready. The write has to be removed
+// from disk with unpersist after the lock has been released.
+// The caller must hold the lock.
+func (q *RetryQueue) complete(write *queuedWrite) {
+	writes := q.pending[write.Key][1:]
+	if len(writes) == 0 {
+		delete(q.pending, write.Key)
+	} else {
+		q.pending[write.Key] = writes
+		q.ready = append(q.ready, write.Key)
+		q.cond.Signal()
+	}
+
+	q.size--
+	q.depth.Set(float64(q.size))
+	if q.size == 0 && q.idle != nil {
+		close(q.idle)
+		q.idle = nil
+	}
+}
+
+func (q *RetryQueue) backoff(attempts int) time.Duration {
+	backoff := q.config.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff = time.Duration(float64(backoff) * q.config.Multiplier)
+		if q.config.MaxBackoff > 0 && backoff >= q.config.MaxBackoff {
+			return q.config.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// restore opens the database of the queue and enqueues all writes that have been persisted before.
+func (q *RetryQueue) restore(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	var err error
+	q.db, err = bolt.Open(path, 0o600, &bolt.Options{Timeout: retryQueueOpenTimeout})
+	if err != nil {
+		return err
+	}
+
+	err = q.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(retryQueueBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.ForEach(func(_, value []byte) error {
+			write := &queuedWrite{ctx: context.Background()}
+			if err := json.Unmarshal(value, write); err != nil {
+				return err
+			}
+
+			q.sequence = max(q.sequence, write.Sequence)
+			q.push(write)
+			return nil
+		})
+	})
+	if err != nil {
+		_ = q.db.Close()
+		return err
+	}
+
+	q.logger.Info().Str("path", path).Int("restored", q.size).Msg("Retry queue restored")
+	return nil
+}
+
+func (q *RetryQueue) persist(write *queuedWrite) error {
+	if q.db == nil || asyncWritesBound(write.ctx) {
+		return nil
+	}
+
+	value, err := json.Marshal(write)
+	if err != nil {
+		return err
+	}
+
+	return q.db.Batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(retryQueueBucket).Put(sequenceKey(write.Sequence), value)
+	})
+}
+
+func (q *RetryQueue) unpersist(write *queuedWrite) error {
+	if q.db == nil || asyncWritesBound(write.ctx) {
+		return nil
+	}
+
+	return q.db.Batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(retryQueueBucket).Delete(sequenceKey(write.Sequence))
+	})
+}
+
+// sequenceKey encodes the sequence big-endian, so that bbolt iterates the writes in the order they were enqueued.
+func sequenceKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	return key
+}
+
+func applyWrite(
+	ctx context.Context,
+	store Store,
+	operation writeOperation,
+	oldObj *unstructured.Unstructured,
+	newObj *unstructured.Unstructured,
+) error {
+	switch operation {
+	case writeOperationCreate:
+		return store.Create(ctx, newObj)
+	case writeOperationUpdate:
+		return store.Update(ctx, oldObj, newObj)
+	case writeOperationDelete:
+		return store.Delete(ctx, oldObj)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+	bolt "go.etcd.io/bbolt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var errFlakyStore = errors.New("flaky store is unavailable")
+
+// flakyStore is a memory store that fails a configurable number of writes, records the applied writes
+// and can be disconnected.
+type flakyStore struct {
+	*MemoryStore
+	mu           sync.Mutex
+	failures     int
+	applied      []string
+	disconnected atomic.Bool
+}
+
+func newFlakyStore(failures int) *flakyStore {
+	store := &flakyStore{MemoryStore: new(MemoryStore), failures: failures}
+	store.Initialize()
+	return store
+}
+
+func (f *flakyStore) record(operation string, obj *unstructured.Unstructured) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		return errFlakyStore
+	}
+	f.applied = append(f.applied, operation+" "+obj.GetName()+" "+obj.GetResourceVersion())
+	return nil
+}
+
+func (f *flakyStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	if err := f.record("create", obj); err != nil {
+		return err
+	}
+	return f.MemoryStore.Create(ctx, obj)
+}
+
+func (f *flakyStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	if err := f.record("update", newObj); err != nil {
+		return err
+	}
+	return f.MemoryStore.Update(ctx, oldObj, newObj)
+}
+
+func (f *flakyStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	if err := f.record("delete", obj); err != nil {
+		return err
+	}
+	return f.MemoryStore.Delete(ctx, obj)
+}
+
+func (f *flakyStore) Connected() bool {
+	return !f.disconnected.Load()
+}
+
+func (f *flakyStore) Applied() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.applied...)
+}
+
+func createRetryQueueConfig() config.RetryQueue {
+	return config.RetryQueue{
+		Capacity:       100,
+		Workers:        2,
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		Multiplier:     2,
+		DrainTimeout:   5 * time.Second,
+	}
+}
+
+func TestRetryQueue_OrderedRetries(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := newFlakyStore(2)
+	queueConfig := createRetryQueueConfig()
+	queue, err := newRetryQueue("test-retry-queue", "flaky", store, &queueConfig)
+	assertions.NoError(err)
+	queue.Start()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	updated := subscriptions[0].DeepCopy()
+	updated.SetResourceVersion("2")
+
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[0])
+	queue.Enqueue(context.Background(), writeOperationUpdate, subscriptions[0], updated)
+	queue.Enqueue(context.Background(), writeOperationDelete, updated, nil)
+	queue.Shutdown()
+
+	assertions.Equal(0, queue.Size(), "queue should be drained on shutdown")
+	assertions.Equal([]string{
+		"create " + subscriptions[0].GetName() + " " + subscriptions[0].GetResourceVersion(),
+		"update " + updated.GetName() + " 2",
+		"delete " + updated.GetName() + " 2",
+	}, store.Applied(), "writes of a resource should be applied in order despite failures")
+
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[1])
+	assertions.Equal(0, queue.Size(), "writes should be dropped after shutdown")
+}
+
+func TestRetryQueue_MaxAttempts(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := newFlakyStore(3)
+	queueConfig := createRetryQueueConfig()
+	queueConfig.MaxAttempts = 3
+	queue, err := newRetryQueue("test-retry-queue", "flaky", store, &queueConfig)
+	assertions.NoError(err)
+	queue.Start()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[0])
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[0])
+	queue.Shutdown()
+
+	assertions.Len(store.Applied(), 1, "first write should be dropped after all attempts failed")
+}
+
+func TestRetryQueue_Capacity(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	queueConfig := createRetryQueueConfig()
+	queueConfig.Capacity = 1
+	queue, err := newRetryQueue("test-retry-queue", "flaky", newFlakyStore(0), &queueConfig)
+	assertions.NoError(err)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[0])
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[1])
+	assertions.Equal(1, queue.Size(), "writes exceeding the capacity should be dropped")
+
+	queue.Start()
+	queue.Shutdown()
+}
+
+func TestRetryQueue_Persistence(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	queueConfig := createRetryQueueConfig()
+	queueConfig.Path = t.TempDir()
+	queueConfig.MaxAttempts = 0
+	queueConfig.DrainTimeout = 50 * time.Millisecond
+
+	unavailable := newFlakyStore(1000)
+	queue, err := newRetryQueue("test-retry-queue", "flaky", unavailable, &queueConfig)
+	assertions.NoError(err)
+	queue.Start()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	for _, subscription := range subscriptions {
+		queue.Enqueue(context.Background(), writeOperationCreate, nil, subscription)
+	}
+	queue.Shutdown()
+	assertions.Equal(len(subscriptions), queue.Size(), "writes should remain pending when the store is unavailable")
+
+	available := newFlakyStore(0)
+	queue, err = newRetryQueue("test-retry-queue", "flaky", available, &queueConfig)
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), queue.Size(), "pending writes should be restored")
+
+	queue.Start()
+	queue.Shutdown()
+
+	count, err := available.Count(context.Background(), utils.GetGroupVersionId(subscriptions[0]))
+	assertions.NoError(err)
+	assertions.Equal(len(subscriptions), count, "restored writes should be applied")
+}
+
+func TestRetryQueue_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(queueConfig *config.RetryQueue)
+	}{
+		{"zero initial backoff", func(queueConfig *config.RetryQueue) { queueConfig.InitialBackoff = 0 }},
+		{"negative initial backoff", func(queueConfig *config.RetryQueue) { queueConfig.InitialBackoff = -time.Second }},
+		{"zero multiplier", func(queueConfig *config.RetryQueue) { queueConfig.Multiplier = 0 }},
+		{"shrinking multiplier", func(queueConfig *config.RetryQueue) { queueConfig.Multiplier = 0.5 }},
+		{"zero drain timeout", func(queueConfig *config.RetryQueue) { queueConfig.DrainTimeout = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions := assert.New(t)
+			defer test.LogRecorder.Reset()
+
+			queueConfig := createRetryQueueConfig()
+			tt.modify(&queueConfig)
+			_, err := newRetryQueue("test-retry-queue", "flaky", newFlakyStore(0), &queueConfig)
+			assertions.ErrorIs(err, ErrInvalidRetryQueueConfig)
+		})
+	}
+}
+
+func TestRetryQueue_CopiesObjects(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := newFlakyStore(0)
+	queueConfig := createRetryQueueConfig()
+	queue, err := newRetryQueue("test-retry-queue", "flaky", store, &queueConfig)
+	assertions.NoError(err)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	obj := subscriptions[0].DeepCopy()
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, obj)
+	obj.SetResourceVersion("modified")
+
+	queue.Start()
+	queue.Shutdown()
+
+	assertions.Equal([]string{
+		"create " + subscriptions[0].GetName() + " " + subscriptions[0].GetResourceVersion(),
+	}, store.Applied(), "changes after enqueueing should not affect the queued write")
+}
+
+func TestRetryQueue_BoundWrites(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	store := newFlakyStore(0)
+	queueConfig := createRetryQueueConfig()
+	queueConfig.Path = t.TempDir()
+	queue, err := newRetryQueue("test-retry-queue", "flaky", store, &queueConfig)
+	assertions.NoError(err)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	ctx, cancel := context.WithCancel(BindAsyncWrites(context.Background()))
+	queue.Enqueue(ctx, writeOperationCreate, nil, subscriptions[0])
+	queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[1])
+	queue.Enqueue(ctx, writeOperationDelete, subscriptions[1], nil)
+	assertions.Equal(3, queue.Size())
+
+	var persisted int
+	assertions.NoError(queue.db.View(func(tx *bolt.Tx) error {
+		persisted = tx.Bucket(retryQueueBucket).Stats().KeyN
+		return nil
+	}))
+	assertions.Equal(1, persisted, "bound writes should not be persisted")
+
+	cancel()
+	queue.Start()
+	queue.Shutdown()
+
+	assertions.Equal(0, queue.Size(), "bound writes should be dropped once their context is done")
+	assertions.Equal([]string{
+		"create " + subscriptions[1].GetName() + " " + subscriptions[1].GetResourceVersion(),
+	}, store.Applied(), "only writes that are not bound should be applied")
+}
+
+func TestRetryQueue_ConcurrentPersistence(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	queueConfig := createRetryQueueConfig()
+	queueConfig.Path = t.TempDir()
+	queueConfig.DrainTimeout = 50 * time.Millisecond
+
+	queue, err := newRetryQueue("test-retry-queue", "flaky", newFlakyStore(1000), &queueConfig)
+	assertions.NoError(err)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			subscription := subscriptions[i%len(subscriptions)].DeepCopy()
+			subscription.SetName(subscription.GetName() + "-" + strconv.Itoa(i))
+			queue.Enqueue(context.Background(), writeOperationCreate, nil, subscription)
+		}()
+	}
+	wg.Wait()
+	assertions.Equal(20, queue.Size())
+	queue.Shutdown()
+
+	queue, err = newRetryQueue("test-retry-queue", "flaky", newFlakyStore(0), &queueConfig)
+	assertions.NoError(err)
+	assertions.Equal(20, queue.Size(), "writes persisted concurrently should be restored")
+	queue.Start()
+	queue.Shutdown()
+}
+
+func TestRetryQueue_ManagerMetrics(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	queueConfig := createRetryQueueConfig()
+	first, err := newRetryQueue("first-manager", "metered", newFlakyStore(0), &queueConfig)
+	assertions.NoError(err)
+	second, err := newRetryQueue("second-manager", "metered", newFlakyStore(0), &queueConfig)
+	assertions.NoError(err)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	first.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[0])
+	first.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[1])
+	second.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[0])
+
+	depth := metrics.GetOrCreateCustom("metered_retry_queue_depth", "manager")
+	assertions.Equal(2.0, testutil.ToFloat64(depth.WithLabelValues("first-manager")))
+	assertions.Equal(1.0, testutil.ToFloat64(depth.WithLabelValues("second-manager")),
+		"queues of different managers should not share their metrics")
+
+	first.Start()
+	second.Start()
+	first.Shutdown()
+	second.Shutdown()
+}
@@ -31,10 +31,12 @@ var hazelcastStore *HazelcastStore
 // NOTE: This creates a global hazelcastStore instance that is shared across all tests.
 // Tests that modify the store state should reset it in defer blocks or at the start.
 func TestMain(m *testing.M) {
-	// Setup Docker containers for MongoDB and Hazelcast
+	// Setup Docker containers for MongoDB, Hazelcast, Redis and PostgreSQL
 	test.SetupDocker(&test.Options{
 		MongoDb:   true,
 		Hazelcast: true,
+		Redis:     true,
+		Postgres:  true,
 	})
 
 	// Initialize the global hazelcast store instance
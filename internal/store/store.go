@@ -5,7 +5,11 @@
 package store
 
 import (
+	"context"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/telekom/quasar/internal/config"
 	reconciler "github.com/telekom/quasar/internal/reconciliation"
@@ -15,17 +19,57 @@ import (
 type Store interface {
 	Initialize()
 	InitializeResource(dataSource reconciler.DataSource, resourceConfig *config.Resource)
-	Create(obj *unstructured.Unstructured) error
-	Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error
-	Delete(obj *unstructured.Unstructured) error
-	Count(dataset string) (int, error)
-	Keys(dataset string) ([]string, error)
-	Read(dataset string, key string) (*unstructured.Unstructured, error)
-	List(dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error)
+	Create(ctx context.Context, obj *unstructured.Unstructured) error
+	Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error
+	Delete(ctx context.Context, obj *unstructured.Unstructured) error
+	Count(ctx context.Context, dataset string) (int, error)
+	Keys(ctx context.Context, dataset string) ([]string, error)
+	Read(ctx context.Context, dataset string, key string) (*unstructured.Unstructured, error)
+	List(ctx context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error)
 	Shutdown()
 	Connected() bool
 }
 
+// writeOnlyStore is implemented by stores that only publish changes and cannot serve reads.
+// Such stores can only be used as sink.
+type writeOnlyStore interface {
+	WriteOnly() bool
+}
+
+func isWriteOnly(store Store) bool {
+	writeOnly, ok := store.(writeOnlyStore)
+	return ok && writeOnly.WriteOnly()
+}
+
+// reconnectNotifier is implemented by stores that notice themselves when they have reconnected or may have lost
+// entries, so that they can be reconciled right away instead of waiting for the next periodic reconciliation.
+type reconnectNotifier interface {
+	OnReconnect(callback func())
+}
+
+// reconnectCallbacks implements reconnectNotifier for stores that embed it.
+type reconnectCallbacks struct {
+	mu        sync.Mutex
+	callbacks []func()
+}
+
+func (c *reconnectCallbacks) OnReconnect(callback func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.callbacks = append(c.callbacks, callback)
+}
+
+// notifyReconnect invokes all registered callbacks.
+func (c *reconnectCallbacks) notifyReconnect() {
+	c.mu.Lock()
+	callbacks := slices.Clone(c.callbacks)
+	c.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
 func createStore(storeType string) (Store, error) {
 	switch strings.ToLower(storeType) {
 	case "redis":
@@ -37,7 +81,76 @@ func createStore(storeType string) (Store, error) {
 	case "mongo":
 		return new(MongoStore), nil
 
+	case "memory":
+		return new(MemoryStore), nil
+
+	case "file":
+		return new(FileStore), nil
+
+	case "postgres":
+		return new(PostgresStore), nil
+
+	case "kafka":
+		return new(KafkaStore), nil
+
+	case "webhook":
+		return new(WebhookStore), nil
+
 	default:
 		return nil, ErrUnknownStoreType
 	}
 }
+
+// operationTimeout returns the configured timeout of single operations of the store type or 0 if there is none.
+func operationTimeout(storeType string) time.Duration {
+	switch strings.ToLower(storeType) {
+	case "redis":
+		return config.Current.Store.Redis.OperationTimeout
+
+	case "hazelcast":
+		return config.Current.Store.Hazelcast.OperationTimeout
+
+	case "mongo":
+		return config.Current.Store.Mongo.OperationTimeout
+
+	case "postgres":
+		return config.Current.Store.Postgres.OperationTimeout
+
+	case "webhook":
+		return config.Current.Store.Webhook.OperationTimeout
+
+	default:
+		return 0
+	}
+}
+
+// storeTimeout returns the time the store itself allows for a single operation, which is either the operation timeout
+// applied by the managed store or, for kafka, the timeout of the producer.
+func storeTimeout(storeType string) time.Duration {
+	if strings.EqualFold(storeType, "kafka") {
+		return config.Current.Store.Kafka.Timeout
+	}
+	return operationTimeout(storeType)
+}
+
+// reconcileMode returns the default reconcile mode of the store type. Only hazelcast has its own setting.
+func reconcileMode(storeType string) config.ReconcileMode {
+	if strings.EqualFold(storeType, "hazelcast") {
+		return config.Current.Store.Hazelcast.ReconcileMode
+	}
+	return config.Current.Reconciliation.Mode
+}
+
+// reconciliationInterval returns the interval of the periodic reconciliation of the store type.
+func reconciliationInterval(storeType string) time.Duration {
+	switch strings.ToLower(storeType) {
+	case "redis":
+		return config.Current.Store.Redis.ReconciliationInterval
+
+	case "hazelcast":
+		return config.Current.Store.Hazelcast.ReconciliationInterval
+
+	default:
+		return config.Current.Reconciliation.Interval
+	}
+}
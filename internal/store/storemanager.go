@@ -5,11 +5,19 @@
 package store
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
 	reconciler "github.com/telekom/quasar/internal/reconciliation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -20,193 +28,512 @@ type DualStore interface {
 	GetSecondary() Store
 }
 
-// DualStoreManager handles primary and secondary store
-type DualStoreManager struct {
-	managerId     string
-	primary       Store
-	secondary     Store
-	primaryType   string
-	secondaryType string
-	mu            sync.RWMutex
-	logger        zerolog.Logger
+// ManagedStore is a store together with the role, write policy and operation timeout it has been configured with.
+type ManagedStore struct {
+	Store
+	Type        string
+	Role        config.StoreRole
+	WritePolicy config.StoreWritePolicy
+	Timeout     time.Duration
+	queue       *RetryQueue
+}
+
+// StoreManager fans out writes to an ordered list of stores. Reads are served by the single authoritative store,
+// while replicas and sinks only receive writes. Writes to stores with a synchronous write policy are awaited,
+// writes to stores with an asynchronous write policy go through a RetryQueue. Whether a write succeeded is decided
+// by the write consistency based on the results of the synchronous writes.
+type StoreManager struct {
+	managerId       string
+	primary         *ManagedStore
+	stores          []*ManagedStore
+	consistency     config.WriteConsistency
+	failover        config.ReadFailover
+	datasets        map[string]bool
+	reconciliations map[string][]*storeReconciliation
+	mu              sync.RWMutex
+	logger          zerolog.Logger
+	ctx             context.Context
+	cancel          context.CancelFunc
+
+	failoverReads prometheus.Counter
+}
+
+// ReadSource is the store a read has been served from. Store managers that serve reads from a replica while the
+// authoritative store is not connected record it in contexts created by WithReadSource.
+type ReadSource struct {
+	// Store is the type of the store that served the read.
+	Store string
+	// Degraded is whether the read has been served by a replica.
+	Degraded bool
+}
+
+type readSourceKey struct{}
+
+// WithReadSource returns a context in which reads record the store they have been served from in the returned source.
+func WithReadSource(ctx context.Context) (context.Context, *ReadSource) {
+	source := new(ReadSource)
+	return context.WithValue(ctx, readSourceKey{}, source), source
+}
+
+// ReadSourceFrom returns the source recorded by reads with the context or nil if the context does not record it.
+func ReadSourceFrom(ctx context.Context) *ReadSource {
+	source, _ := ctx.Value(readSourceKey{}).(*ReadSource)
+	return source
+}
+
+type boundWritesKey struct{}
+
+// BindAsyncWrites returns a context to which the writes queued for asynchronous stores are bound. Queued writes are
+// dropped instead of applied once the context is done, e.g. when a watcher that enqueued them has lost its leadership.
+// Bound writes are not persisted, as they are only valid as long as the context is.
+func BindAsyncWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, boundWritesKey{}, true)
+}
+
+// asyncWritesBound returns whether writes with the context are bound to it.
+func asyncWritesBound(ctx context.Context) bool {
+	bound, _ := ctx.Value(boundWritesKey{}).(bool)
+	return bound
+}
+
+// DualStoreManager is the store manager of a primary and an optional secondary store.
+type DualStoreManager = StoreManager
+
+// SetupStoreManager creates a manager for the stores of the given configuration.
+func SetupStoreManager(id string, storeConfig *config.DualStore) (DualStore, error) {
+	return setupStoreManager(id, storeConfig)
 }
 
+// SetupDualStoreManager creates a manager with the primary store as authoritative store and the secondary store,
+// if it is set and differs from the primary store, as replica (or sink, if it is write-only).
 func SetupDualStoreManager(id string, primaryType, secondaryType string) (DualStore, error) {
-	if primaryType == "" {
-		return nil, ErrUnknownStoreType
+	storeConfig := config.DualStore{
+		Primary:   config.Store{Type: primaryType},
+		Secondary: config.Store{Type: secondaryType},
 	}
+	return setupStoreManager(id, &storeConfig)
+}
 
-	// Create structured logger with context
-	logger := log.With().
-		Str("component", "DualStoreManager").
-		Str("id", id).
-		Str("primaryType", primaryType).
-		Str("secondaryType", secondaryType).
-		Logger()
+func setupStoreManager(id string, managerConfig *config.DualStore) (*StoreManager, error) {
+	storeConfigs := managerConfig.GetStores()
+	if len(storeConfigs) == 0 {
+		return nil, ErrUnknownStoreType
+	}
 
-	// Create primary store
-	primary, err := createStore(primaryType)
-	if err != nil {
-		logger.Fatal().Err(err).
-			Msg("Could not create primary store!")
-		return nil, err
+	consistency := managerConfig.Consistency
+	switch consistency {
+	case "":
+		consistency = config.WriteConsistencyPrimaryOnlyAck
+	case config.WriteConsistencyPrimaryOnlyAck, config.WriteConsistencyAllAck, config.WriteConsistencyAnyAck:
+	default:
+		return nil, ErrUnknownWriteConsistency
 	}
 
-	// Create secondary store
-	var secondary Store
-	if secondaryType != "" && secondaryType != primaryType {
-		secondary, err = createStore(secondaryType)
+	manager := newStoreManager(id, storeTypes(storeConfigs), consistency, managerConfig.ReadFailover)
+	logger := manager.logger
+
+	seen := make(map[string]bool, len(storeConfigs))
+	for _, storeConfig := range storeConfigs {
+		if seen[storeConfig.Type] {
+			logger.Warn().Str("storeType", storeConfig.Type).Msg("Store is configured more than once, ignoring duplicate")
+			continue
+		}
+		seen[storeConfig.Type] = true
+
+		store, err := createStore(storeConfig.Type)
 		if err != nil {
-			logger.Fatal().Err(err).
-				Msg("Could not create secondary store!")
+			logger.Fatal().Err(err).Str("storeType", storeConfig.Type).
+				Msg("Could not create store!")
 			return nil, err
 		}
+
+		managedStore, err := newManagedStore(store, &storeConfig, consistency)
+		if err != nil {
+			return nil, fmt.Errorf("store %q: %w", storeConfig.Type, err)
+		}
+
+		managedStore.Timeout = operationTimeout(storeConfig.Type)
+		if breakerConfig := &config.Current.Store.CircuitBreaker; breakerConfig.Enabled {
+			managedStore.Store = newCircuitBreakerStore(id, storeConfig.Type, store, breakerConfig, storeTimeout(storeConfig.Type))
+		}
+
+		if managedStore.Role == config.StoreRoleAuthoritative {
+			if manager.primary != nil {
+				return nil, ErrMultipleAuthoritativeStores
+			}
+			manager.primary = managedStore
+		}
+		manager.stores = append(manager.stores, managedStore)
+	}
+
+	if manager.primary == nil {
+		return nil, ErrMissingAuthoritativeStore
 	}
 
-	// Create and return the DualStoreManager
-	manager := &DualStoreManager{
-		managerId:     id,
-		primary:       primary,
-		secondary:     secondary,
-		primaryType:   primaryType,
-		secondaryType: secondaryType,
-		mu:            sync.RWMutex{},
-		logger:        logger,
+	for _, store := range manager.stores {
+		if store.WritePolicy != config.StoreWritePolicyAsync {
+			continue
+		}
+
+		var err error
+		if store.queue, err = newRetryQueue(id, store.Type, store, &config.Current.Store.RetryQueue); err != nil {
+			logger.Fatal().Err(err).Str("storeType", store.Type).
+				Msg("Could not create retry queue!")
+			return nil, err
+		}
 	}
 
 	manager.Initialize()
-	logger.Debug().Msg("Successfully created dual store manager")
+
+	if verification := managerConfig.Verification; verification.Enabled && verification.Interval > 0 {
+		go manager.verifyPeriodically(verification.Interval, verification.Repair)
+	}
+
+	logger.Debug().Msg("Successfully created store manager")
 	return manager, nil
 }
 
-func (m *DualStoreManager) Initialize() {
-	m.primary.Initialize()
+func newStoreManager(
+	id string,
+	storeTypes []string,
+	consistency config.WriteConsistency,
+	failover config.ReadFailover,
+) *StoreManager {
+	// Create structured logger with context
+	logger := log.With().
+		Str("component", "StoreManager").
+		Str("id", id).
+		Strs("storeTypes", storeTypes).
+		Str("consistency", string(consistency)).
+		Logger()
 
-	if m.secondary != nil {
-		m.secondary.Initialize()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StoreManager{
+		managerId:       id,
+		consistency:     consistency,
+		failover:        failover,
+		datasets:        make(map[string]bool),
+		reconciliations: make(map[string][]*storeReconciliation),
+		mu:              sync.RWMutex{},
+		logger:          logger,
+		ctx:             ctx,
+		cancel:          cancel,
+		failoverReads:   metrics.GetOrCreateCustomCounter("store_failover_reads_total").WithLabelValues(),
 	}
 }
 
-func (m *DualStoreManager) InitializeResource(dataSource reconciler.DataSource, resourceConfig *config.Resource) {
-	m.primary.InitializeResource(dataSource, resourceConfig)
+// newManagedStore applies the defaults of the role and write policy and validates them.
+// Stores without role are replicas, unless they are write-only, in which case they are sinks.
+// The authoritative store is always written synchronously. All other stores are written asynchronously by default,
+// unless the write consistency requires their acknowledgement.
+func newManagedStore(store Store, storeConfig *config.Store, consistency config.WriteConsistency) (*ManagedStore, error) {
+	managedStore := &ManagedStore{
+		Store:       store,
+		Type:        storeConfig.Type,
+		Role:        storeConfig.Role,
+		WritePolicy: storeConfig.WritePolicy,
+	}
 
-	if m.secondary != nil {
-		m.secondary.InitializeResource(dataSource, resourceConfig)
+	if managedStore.Role == "" {
+		managedStore.Role = config.StoreRoleReplica
+		if isWriteOnly(store) {
+			managedStore.Role = config.StoreRoleSink
+		}
 	}
-}
 
-func (m *DualStoreManager) Create(obj *unstructured.Unstructured) error {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	if managedStore.WritePolicy == "" {
+		managedStore.WritePolicy = config.StoreWritePolicyAsync
+		if managedStore.Role == config.StoreRoleAuthoritative || consistency != config.WriteConsistencyPrimaryOnlyAck {
+			managedStore.WritePolicy = config.StoreWritePolicySync
+		}
+	}
 
-	var primaryErr error
-	if primaryErr = m.primary.Create(obj); primaryErr != nil {
-		m.logPrimaryError("Create", primaryErr)
+	switch managedStore.Role {
+	case config.StoreRoleAuthoritative:
+		if isWriteOnly(store) {
+			return nil, ErrWriteOnlyPrimary
+		}
+		if managedStore.WritePolicy != config.StoreWritePolicySync {
+			return nil, ErrAsyncAuthoritativeStore
+		}
+	case config.StoreRoleReplica:
+		if isWriteOnly(store) {
+			return nil, ErrWriteOnlyReplica
+		}
+	case config.StoreRoleSink:
+	default:
+		return nil, ErrUnknownStoreRole
 	}
 
-	if m.secondary != nil {
-		go func() {
-			if secondaryErr := m.secondary.Create(obj); secondaryErr != nil {
-				m.logSecondaryError("Create", secondaryErr)
-			}
-		}()
+	switch managedStore.WritePolicy {
+	case config.StoreWritePolicySync, config.StoreWritePolicyAsync:
+	default:
+		return nil, ErrUnknownStoreWritePolicy
 	}
-	return primaryErr
+
+	return managedStore, nil
 }
 
-func (m *DualStoreManager) Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+func (s *ManagedStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.Create(ctx, obj)
+}
+
+func (s *ManagedStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.Update(ctx, oldObj, newObj)
+}
+
+func (s *ManagedStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.Delete(ctx, obj)
+}
+
+func (s *ManagedStore) Count(ctx context.Context, dataset string) (int, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.Count(ctx, dataset)
+}
 
-	var primaryErr error
+func (s *ManagedStore) Keys(ctx context.Context, dataset string) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.Keys(ctx, dataset)
+}
+
+func (s *ManagedStore) Read(ctx context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.Read(ctx, dataset, key)
+}
+
+func (s *ManagedStore) List(ctx context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Store.List(ctx, dataset, fieldSelector, limit)
+}
 
-	if primaryErr = m.primary.Update(oldObj, newObj); primaryErr != nil {
-		m.logPrimaryError("Update", primaryErr)
+// withTimeout derives a context that is cancelled once the operation timeout of the store has passed.
+func (s *ManagedStore) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeoutCause(ctx, s.Timeout, errOperationTimeout)
+}
 
-	if m.secondary != nil {
-		go func() {
-			if secondaryErr := m.secondary.Update(oldObj, newObj); secondaryErr != nil {
-				m.logSecondaryError("Update", secondaryErr)
-			}
-		}()
+func storeTypes(storeConfigs []config.Store) []string {
+	types := make([]string, 0, len(storeConfigs))
+	for _, storeConfig := range storeConfigs {
+		types = append(types, storeConfig.Type)
 	}
-	return primaryErr
+	return types
+}
+
+func (m *StoreManager) Initialize() {
+	for _, store := range m.stores {
+		store.Initialize()
+		if store.queue != nil {
+			store.queue.Start()
+		}
+		if store.Role != config.StoreRoleSink {
+			m.watchReconnects(store)
+		}
+	}
+}
+
+// InitializeResource initializes the resource in all stores and starts the reconciliation of the stores with the
+// data source, which runs until the manager is shut down.
+func (m *StoreManager) InitializeResource(dataSource reconciler.DataSource, resourceConfig *config.Resource) {
+	m.InitializeStores(dataSource, resourceConfig)
+	m.StartReconciliation(m.ctx, dataSource, resourceConfig)
+}
+
+// InitializeStores initializes the resource in all stores without reconciling them.
+func (m *StoreManager) InitializeStores(dataSource reconciler.DataSource, resourceConfig *config.Resource) {
+	m.mu.Lock()
+	m.datasets[resourceConfig.GetGroupVersionName()] = true
+	m.mu.Unlock()
+
+	for _, store := range m.stores {
+		store.InitializeResource(dataSource, resourceConfig)
+	}
+}
+
+func (m *StoreManager) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	return m.write(ctx, writeOperationCreate, nil, obj)
+}
+
+func (m *StoreManager) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	return m.write(ctx, writeOperationUpdate, oldObj, newObj)
+}
+
+func (m *StoreManager) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	return m.write(ctx, writeOperationDelete, obj, nil)
 }
 
-func (m *DualStoreManager) Delete(obj *unstructured.Unstructured) error {
+// write applies the operation to the authoritative store first and then to all other stores in the configured order.
+// Depending on the write consistency, the error of the authoritative store, the errors of all synchronously written
+// stores or, if none of them has acknowledged the write, the errors of all of them are returned.
+func (m *StoreManager) write(ctx context.Context, operation writeOperation, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var primaryErr error
+	var errs []error
+	acknowledged := 0
 
-	if primaryErr = m.primary.Delete(obj); primaryErr != nil {
-		m.logPrimaryError("Update", primaryErr)
+	primaryErr := applyWrite(ctx, m.primary, operation, oldObj, newObj)
+	if primaryErr != nil {
+		m.logStoreError(m.primary, operation, primaryErr)
+		errs = append(errs, primaryErr)
+	} else {
+		acknowledged++
 	}
 
-	if m.secondary != nil {
-		go func() {
-			if secondaryErr := m.secondary.Delete(obj); secondaryErr != nil {
-				m.logSecondaryError("Update", secondaryErr)
-			}
-		}()
+	for _, store := range m.stores {
+		if store == m.primary {
+			continue
+		}
+
+		if store.queue != nil {
+			store.queue.Enqueue(ctx, operation, oldObj, newObj)
+			continue
+		}
+
+		if err := applyWrite(ctx, store, operation, oldObj, newObj); err != nil {
+			m.logStoreError(store, operation, err)
+			errs = append(errs, err)
+		} else {
+			acknowledged++
+		}
 	}
 
-	return primaryErr
+	switch m.consistency {
+	case config.WriteConsistencyAllAck:
+		return errors.Join(errs...)
+	case config.WriteConsistencyAnyAck:
+		if acknowledged > 0 {
+			return nil
+		}
+		return errors.Join(errs...)
+	default:
+		return primaryErr
+	}
 }
 
-func (m *DualStoreManager) Count(dataset string) (int, error) {
+func (m *StoreManager) Count(ctx context.Context, dataset string) (int, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.primary.Count(dataset)
+	return m.readStore(ctx).Count(ctx, dataset)
 }
 
-func (m *DualStoreManager) Keys(dataset string) ([]string, error) {
+func (m *StoreManager) Keys(ctx context.Context, dataset string) ([]string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.primary.Keys(dataset)
+	return m.readStore(ctx).Keys(ctx, dataset)
 }
 
-func (m *DualStoreManager) Read(dataset string, name string) (*unstructured.Unstructured, error) {
+func (m *StoreManager) Read(ctx context.Context, dataset string, name string) (*unstructured.Unstructured, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.primary.Read(dataset, name)
+	return m.readStore(ctx).Read(ctx, dataset, name)
 }
 
-func (m *DualStoreManager) List(dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+func (m *StoreManager) List(ctx context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.primary.List(dataset, fieldSelector, limit)
+	return m.readStore(ctx).List(ctx, dataset, fieldSelector, limit)
 }
 
-func (m *DualStoreManager) Shutdown() {
-	m.primary.Shutdown()
+// Shutdown stops the periodic verification and reconciliation and drains the retry queues before the stores are shut down.
+func (m *StoreManager) Shutdown() {
+	if m.cancel != nil {
+		m.cancel()
+	}
 
-	if m.secondary != nil {
-		m.secondary.Shutdown()
+	for _, store := range m.stores {
+		if store.queue != nil {
+			store.queue.Shutdown()
+		}
+		store.Shutdown()
 	}
 }
 
-func (m *DualStoreManager) Connected() bool {
+// getDatasets returns the datasets of all resources the stores have been initialized for.
+func (m *StoreManager) getDatasets() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(m.datasets))
+}
+
+func (m *StoreManager) Connected() bool {
 	return m.primary.Connected()
 }
 
-func (m *DualStoreManager) GetPrimary() Store {
-	return m.primary
+// readStore returns the authoritative store or, if it is not connected and read failover is enabled,
+// the first connected replica that satisfies the staleness policy. The store is recorded as source of the read.
+func (m *StoreManager) readStore(ctx context.Context) *ManagedStore {
+	store, degraded := m.selectReadStore()
+	if degraded {
+		m.failoverReads.Inc()
+		m.logger.Debug().Str("storeType", store.Type).Msg("Authoritative store is not connected, reading from replica")
+	}
+	if source := ReadSourceFrom(ctx); source != nil {
+		source.Store, source.Degraded = store.Type, degraded
+	}
+	return store
 }
 
-func (m *DualStoreManager) GetSecondary() Store {
-	return m.secondary
+func (m *StoreManager) selectReadStore() (*ManagedStore, bool) {
+	if !m.failover.Enabled || m.primary.Connected() {
+		return m.primary, false
+	}
+
+	for _, store := range m.stores {
+		if store.Role != config.StoreRoleReplica || !store.Connected() {
+			continue
+		}
+
+		if m.failover.MaxStaleness > 0 && store.queue != nil && store.queue.Lag() > m.failover.MaxStaleness {
+			continue
+		}
+		return store, true
+	}
+
+	return m.primary, false
+}
+
+// GetPrimary returns the authoritative store.
+func (m *StoreManager) GetPrimary() Store {
+	return m.primary.Store
+}
+
+// GetSecondary returns the first store that is not authoritative or nil if there is none.
+func (m *StoreManager) GetSecondary() Store {
+	for _, store := range m.stores {
+		if store != m.primary {
+			return store.Store
+		}
+	}
+	return nil
 }
 
-func (m *DualStoreManager) logPrimaryError(operation string, err error) {
-	m.logger.Warn().Err(err).Str("operation", operation).Msg("Primary store operation failed")
+// GetStores returns all managed stores in the configured order.
+func (m *StoreManager) GetStores() []*ManagedStore {
+	return m.stores
 }
 
-func (m *DualStoreManager) logSecondaryError(operation string, err error) {
-	m.logger.Warn().Err(err).Str("operation", operation).Msg("Secondary store operation failed")
+func (m *StoreManager) logStoreError(store *ManagedStore, operation writeOperation, err error) {
+	m.logger.Warn().Err(err).
+		Str("operation", string(operation)).
+		Str("storeType", store.Type).
+		Str("role", string(store.Role)).
+		Msg("Store operation failed")
 }
@@ -9,6 +9,9 @@ package store
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,6 +20,8 @@ import (
 	"github.com/telekom/quasar/internal/config"
 	"github.com/telekom/quasar/internal/reconciliation"
 	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // TestSetupDualStoreManager tests the SetupDualStoreManager function
@@ -157,7 +162,7 @@ func TestDualStoreManagerCreate(t *testing.T) {
 	resource := test.CreateTestResource("test-resource", "default", nil)
 
 	// Create should succeed or fail based on primary store
-	_ = manager.Create(resource)
+	_ = manager.Create(context.Background(), resource)
 	// We don't assert on error here as it depends on MongoDB availability
 	assertions.NotNil(manager)
 }
@@ -179,7 +184,7 @@ func TestDualStoreManagerUpdate(t *testing.T) {
 	newResource := test.CreateTestResource("test-resource", "default", map[string]string{"updated": "true"})
 
 	// Update should succeed or fail based on primary store
-	_ = manager.Update(oldResource, newResource)
+	_ = manager.Update(context.Background(), oldResource, newResource)
 	// We don't assert on error here as it depends on MongoDB availability
 	assertions.NotNil(manager)
 }
@@ -200,7 +205,7 @@ func TestDualStoreManagerDelete(t *testing.T) {
 	resource := test.CreateTestResource("test-resource", "default", nil)
 
 	// Delete should succeed or fail based on primary store
-	_ = manager.Delete(resource)
+	_ = manager.Delete(context.Background(), resource)
 	// We don't assert on error here as it depends on MongoDB availability
 	assertions.NotNil(manager)
 }
@@ -219,7 +224,7 @@ func TestDualStoreManagerCount(t *testing.T) {
 	assertions.NoError(err)
 	defer manager.Shutdown()
 
-	_, _ = manager.Count("test-collection")
+	_, _ = manager.Count(context.Background(), "test-collection")
 	assertions.NotNil(manager)
 	// Count result depends on MongoDB
 }
@@ -238,7 +243,7 @@ func TestDualStoreManagerKeys(t *testing.T) {
 	defer manager.Shutdown()
 
 	// Keys should read from primary store
-	keys, _ := manager.Keys("test-collection")
+	keys, _ := manager.Keys(context.Background(), "test-collection")
 	assertions.NotNil(manager)
 	// Keys result depends on MongoDB
 	_ = keys
@@ -258,7 +263,7 @@ func TestDualStoreManagerRead(t *testing.T) {
 	defer manager.Shutdown()
 
 	// Read should read from primary store
-	result, _ := manager.Read("test-collection", "test-key")
+	result, _ := manager.Read(context.Background(), "test-collection", "test-key")
 	assertions.NotNil(manager)
 	// Result depends on MongoDB
 	_ = result
@@ -278,7 +283,7 @@ func TestDualStoreManagerList(t *testing.T) {
 	defer manager.Shutdown()
 
 	// List should read from primary store
-	results, _ := manager.List("test-collection", "", 0)
+	results, _ := manager.List(context.Background(), "test-collection", "", 0)
 	assertions.NotNil(manager)
 	// Results depend on MongoDB
 	_ = results
@@ -338,7 +343,7 @@ func TestDualStoreManagerGetPrimary(t *testing.T) {
 
 	primary := manager.GetPrimary()
 	assertions.NotNil(primary)
-	assertions.IsType(&MongoStore{}, primary)
+	assertions.IsType(&MongoStore{}, unwrapStore(primary))
 }
 
 // TestDualStoreManagerGetSecondary tests getting the secondary store
@@ -356,7 +361,7 @@ func TestDualStoreManagerGetSecondary(t *testing.T) {
 
 	secondary := manager.GetSecondary()
 	assertions.NotNil(secondary)
-	assertions.IsType(&HazelcastStore{}, secondary)
+	assertions.IsType(&HazelcastStore{}, unwrapStore(secondary))
 }
 
 // TestDualStoreManagerGetSecondaryNil tests getting nil secondary store
@@ -406,7 +411,7 @@ func TestDualStoreManagerAsyncSecondaryWrite(t *testing.T) {
 
 	done := make(chan error)
 	go func() {
-		done <- manager.Create(resource)
+		done <- manager.Create(context.Background(), resource)
 	}()
 
 	select {
@@ -457,7 +462,7 @@ func TestDualStoreManagerConcurrency(t *testing.T) {
 
 	for i := range 10 {
 		go func(index int) {
-			_, err := manager.Read(
+			_, err := manager.Read(context.Background(),
 				fmt.Sprintf("collection-%d", index),
 				fmt.Sprintf("key-%d", index),
 			)
@@ -472,3 +477,380 @@ func TestDualStoreManagerConcurrency(t *testing.T) {
 
 	assertions.NotNil(manager, "concurrent access should not panic")
 }
+
+// TestSetupStoreManager tests the fan-out to an authoritative store and a synchronous sink
+func TestSetupStoreManager(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	previousWebhookConfig := config.Current.Store.Webhook
+	config.Current.Store.Webhook = config.Webhook{Url: server.URL, Timeout: 5 * time.Second}
+	defer func() {
+		config.Current.Store.Webhook = previousWebhookConfig
+	}()
+
+	manager, err := SetupStoreManager("test-manager-fan-out", &config.DualStore{
+		Stores: []config.Store{
+			{Type: "webhook"},
+			{Type: "memory", Role: config.StoreRoleAuthoritative},
+		},
+		Consistency: config.WriteConsistencyAllAck,
+	})
+	assertions.NoError(err)
+	defer manager.Shutdown()
+
+	stores := manager.(*StoreManager).GetStores()
+	if assertions.Len(stores, 2) {
+		assertions.Equal(config.StoreRoleSink, stores[0].Role, "write-only stores without role should be sinks")
+		assertions.Equal(config.StoreWritePolicySync, stores[0].WritePolicy, "all-ack should write all stores synchronously")
+		assertions.Equal(config.StoreRoleAuthoritative, stores[1].Role)
+		assertions.Equal(config.StoreWritePolicySync, stores[1].WritePolicy, "authoritative store should be written synchronously")
+	}
+	assertions.IsType(&MemoryStore{}, unwrapStore(manager.GetPrimary()))
+	assertions.IsType(&WebhookStore{}, unwrapStore(manager.GetSecondary()))
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	assertions.NoError(manager.Create(context.Background(), subscriptions[0]))
+	assertions.Equal(int32(1), requests.Load(), "synchronous sink should have been written")
+
+	count, err := manager.Count(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.Equal(1, count, "reads should be served by the authoritative store")
+
+	status.Store(http.StatusBadRequest)
+	err = manager.Create(context.Background(), subscriptions[1])
+	assertions.ErrorIs(err, ErrWebhookDeliveryFailed, "all-ack should fail if a store did not acknowledge the write")
+
+	count, _ = manager.Count(context.Background(), dataset)
+	assertions.Equal(2, count, "authoritative store should be written regardless of failing sinks")
+}
+
+// TestSetupStoreManagerInvalidConfig tests the validation of roles and write policies
+func TestSetupStoreManagerInvalidConfig(t *testing.T) {
+	defer test.LogRecorder.Reset()
+
+	var testCases = []struct {
+		name     string
+		stores   []config.Store
+		expected error
+	}{
+		{"no stores", nil, ErrUnknownStoreType},
+		{"no authoritative store", []config.Store{{Type: "memory"}}, ErrMissingAuthoritativeStore},
+		{
+			"multiple authoritative stores",
+			[]config.Store{{Type: "memory", Role: config.StoreRoleAuthoritative}, {Type: "file", Role: config.StoreRoleAuthoritative}},
+			ErrMultipleAuthoritativeStores,
+		},
+		{"write-only authoritative store", []config.Store{{Type: "webhook", Role: config.StoreRoleAuthoritative}}, ErrWriteOnlyPrimary},
+		{"write-only replica", []config.Store{{Type: "webhook", Role: config.StoreRoleReplica}}, ErrWriteOnlyReplica},
+		{
+			"asynchronous authoritative store",
+			[]config.Store{{Type: "memory", Role: config.StoreRoleAuthoritative, WritePolicy: config.StoreWritePolicyAsync}},
+			ErrAsyncAuthoritativeStore,
+		},
+		{"unknown role", []config.Store{{Type: "memory", Role: "leader"}}, ErrUnknownStoreRole},
+		{"unknown write policy", []config.Store{{Type: "memory", WritePolicy: "eventually"}}, ErrUnknownStoreWritePolicy},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := SetupStoreManager("test-manager-invalid", &config.DualStore{Stores: testCase.stores})
+			assert.ErrorIs(t, err, testCase.expected)
+		})
+	}
+
+	_, err := SetupStoreManager("test-manager-invalid", &config.DualStore{
+		Stores:      []config.Store{{Type: "memory", Role: config.StoreRoleAuthoritative}},
+		Consistency: "quorum",
+	})
+	assert.ErrorIs(t, err, ErrUnknownWriteConsistency)
+}
+
+// TestStoreManagerWriteConsistency tests which errors are returned for each write consistency
+func TestStoreManagerWriteConsistency(t *testing.T) {
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+
+	var testCases = []struct {
+		consistency     config.WriteConsistency
+		primaryFails    bool
+		secondaryFails  bool
+		expectedFailure bool
+	}{
+		{config.WriteConsistencyPrimaryOnlyAck, false, true, false},
+		{config.WriteConsistencyPrimaryOnlyAck, true, false, true},
+		{config.WriteConsistencyAllAck, false, true, true},
+		{config.WriteConsistencyAllAck, true, false, true},
+		{config.WriteConsistencyAllAck, false, false, false},
+		{config.WriteConsistencyAnyAck, false, true, false},
+		{config.WriteConsistencyAnyAck, true, false, false},
+		{config.WriteConsistencyAnyAck, true, true, true},
+	}
+
+	for _, testCase := range testCases {
+		name := fmt.Sprintf("%s/primaryFails=%t/secondaryFails=%t", testCase.consistency, testCase.primaryFails, testCase.secondaryFails)
+		t.Run(name, func(t *testing.T) {
+			failures := func(fails bool) int {
+				if fails {
+					return 1
+				}
+				return 0
+			}
+
+			primary := &ManagedStore{
+				Store:       newFlakyStore(failures(testCase.primaryFails)),
+				Type:        "primary",
+				Role:        config.StoreRoleAuthoritative,
+				WritePolicy: config.StoreWritePolicySync,
+			}
+			secondary := &ManagedStore{
+				Store:       newFlakyStore(failures(testCase.secondaryFails)),
+				Type:        "secondary",
+				Role:        config.StoreRoleReplica,
+				WritePolicy: config.StoreWritePolicySync,
+			}
+			manager := &StoreManager{
+				primary:     primary,
+				stores:      []*ManagedStore{primary, secondary},
+				consistency: testCase.consistency,
+			}
+
+			err := manager.Create(context.Background(), subscriptions[0])
+			if testCase.expectedFailure {
+				assert.ErrorIs(t, err, errFlakyStore)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestDualStoreGetStores tests that primary and secondary store are translated into a list of stores
+func TestDualStoreGetStores(t *testing.T) {
+	assertions := assert.New(t)
+
+	dualStore := config.DualStore{Primary: config.Store{Type: "memory"}, Secondary: config.Store{Type: "kafka"}}
+	stores := dualStore.GetStores()
+	if assertions.Len(stores, 2) {
+		assertions.Equal(config.Store{Type: "memory", Role: config.StoreRoleAuthoritative}, stores[0])
+		assertions.Equal(config.Store{Type: "kafka"}, stores[1])
+	}
+
+	dualStore.Secondary.Type = "memory"
+	assertions.Len(dualStore.GetStores(), 1, "secondary store of the same type should be ignored")
+
+	dualStore.Stores = []config.Store{{Type: "redis", Role: config.StoreRoleAuthoritative}}
+	assertions.Equal(dualStore.Stores, dualStore.GetStores(), "list of stores should take precedence")
+}
+
+// TestStoreManagerReadFailover tests that reads are served by a replica while the authoritative store is disconnected
+func TestStoreManagerReadFailover(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	primaryStore := newFlakyStore(0)
+	replicaStore := newFlakyStore(0)
+	assertions.NoError(replicaStore.Create(context.Background(), subscriptions[0]))
+
+	failover := config.ReadFailover{Enabled: true, MaxStaleness: time.Minute}
+	manager := newStoreManager("test-manager-failover", []string{"primary", "replica"}, config.WriteConsistencyPrimaryOnlyAck, failover)
+	manager.primary = &ManagedStore{Store: primaryStore, Type: "primary", Role: config.StoreRoleAuthoritative}
+	replica := &ManagedStore{Store: replicaStore, Type: "replica", Role: config.StoreRoleReplica}
+	sink := &ManagedStore{Store: newFlakyStore(0), Type: "sink", Role: config.StoreRoleSink}
+	manager.stores = []*ManagedStore{manager.primary, sink, replica}
+
+	// readSource returns the store that served a read and whether it has been degraded
+	readSource := func() (string, bool) {
+		ctx, source := WithReadSource(context.Background())
+		_, _ = manager.Keys(ctx, dataset)
+		return source.Store, source.Degraded
+	}
+
+	ctx, source := WithReadSource(context.Background())
+	count, err := manager.Count(ctx, dataset)
+	assertions.NoError(err)
+	assertions.Equal(0, count, "connected authoritative store should serve reads")
+	assertions.Equal(ReadSource{Store: "primary"}, *source)
+
+	primaryStore.disconnected.Store(true)
+	ctx, source = WithReadSource(context.Background())
+	count, err = manager.Count(ctx, dataset)
+	assertions.NoError(err)
+	assertions.Equal(1, count, "replica should serve reads while the authoritative store is disconnected")
+	assertions.Equal(ReadSource{Store: "replica", Degraded: true}, *source, "reads should record the replica serving them")
+
+	queueConfig := createRetryQueueConfig()
+	replica.queue, err = newRetryQueue("test-manager-failover", "replica", replicaStore, &queueConfig)
+	assertions.NoError(err)
+	replica.queue.Enqueue(context.Background(), writeOperationCreate, nil, subscriptions[1])
+	replica.queue.pending[replica.queue.ready[0]][0].Enqueued = time.Now().Add(-time.Hour)
+	_, degraded := readSource()
+	assertions.False(degraded, "replicas lagging behind more than the maximum staleness should not serve reads")
+
+	manager.failover.MaxStaleness = 0
+	_, degraded = readSource()
+	assertions.True(degraded, "staleness should be ignored without maximum staleness")
+
+	replicaStore.disconnected.Store(true)
+	_, degraded = readSource()
+	assertions.False(degraded, "disconnected replicas should not serve reads")
+
+	manager.failover.Enabled = false
+	primaryStore.disconnected.Store(false)
+	replicaStore.disconnected.Store(false)
+	_, degraded = readSource()
+	assertions.False(degraded)
+}
+
+// deadlineStore is a memory store whose reads wait until their context is done.
+type deadlineStore struct {
+	*MemoryStore
+}
+
+func (d *deadlineStore) Read(ctx context.Context, _ string, _ string) (*unstructured.Unstructured, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestManagedStoreOperationTimeout tests that operations of a managed store are cancelled after its timeout
+func TestManagedStoreOperationTimeout(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	managedStore := &ManagedStore{Store: &deadlineStore{MemoryStore: new(MemoryStore)}, Type: "deadline", Timeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, err := managedStore.Read(context.Background(), "dataset", "key")
+	assertions.ErrorIs(err, context.DeadlineExceeded)
+	assertions.Less(time.Since(start), time.Second, "operation should be cancelled after the operation timeout")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = managedStore.Read(ctx, "dataset", "key")
+	assertions.ErrorIs(err, context.Canceled, "cancellation of the caller should be propagated")
+}
+
+// TestStoreManagerReconciliation tests that the replicas of a manager are reconciled against its authoritative store
+func TestStoreManagerReconciliation(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	mode := config.Current.Mode
+	config.Current.Mode = config.ModeProvisioning
+	defer func() { config.Current.Mode = mode }()
+	hazelcastMode := config.Current.Store.Hazelcast.ReconcileMode
+	config.Current.Store.Hazelcast.ReconcileMode = config.ReconcileModeFull
+	defer func() { config.Current.Store.Hazelcast.ReconcileMode = hazelcastMode }()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+	resourceConfig := config.Resource{}
+	resourceConfig.Kubernetes.Group = subscriptions[0].GroupVersionKind().Group
+	resourceConfig.Kubernetes.Version = subscriptions[0].GroupVersionKind().Version
+	resourceConfig.Kubernetes.Resource = "subscriptions"
+	resourceConfig.Kubernetes.Kind = subscriptions[0].GetKind()
+
+	primaryStore := newFlakyStore(0)
+	for _, subscription := range subscriptions[:2] {
+		assertions.NoError(primaryStore.Create(context.Background(), subscription))
+	}
+	replicaStore := newFlakyStore(0)
+	sinkStore := newFlakyStore(0)
+
+	manager := newStoreManager("test-manager-reconciliation", []string{"primary", "replica", "sink"}, config.WriteConsistencyPrimaryOnlyAck, config.ReadFailover{})
+	defer manager.cancel()
+	manager.primary = &ManagedStore{Store: primaryStore, Type: "primary", Role: config.StoreRoleAuthoritative}
+	replica := &ManagedStore{Store: replicaStore, Type: "replica", Role: config.StoreRoleReplica}
+	manager.stores = []*ManagedStore{manager.primary, replica, {Store: sinkStore, Type: "sink", Role: config.StoreRoleSink}}
+
+	dataSource := reconciliation.NewDataSourceFromStore(manager, resourceConfig)
+	reports, err := manager.DiffReconciliation(context.Background(), dataSource, &resourceConfig)
+	assertions.NoError(err)
+	if assertions.Len(reports, 1, "only the replica should be compared with the authoritative store") {
+		assertions.Equal("replica", reports[0].Store)
+		assertions.ElementsMatch([]string{subscriptions[0].GetName(), subscriptions[1].GetName()}, reports[0].Missing)
+	}
+	assertions.Empty(replicaStore.Applied(), "diff should not write anything")
+
+	manager.InitializeResource(dataSource, &resourceConfig)
+	keys, err := replicaStore.Keys(context.Background(), dataset)
+	assertions.NoError(err)
+	assertions.ElementsMatch([]string{subscriptions[0].GetName(), subscriptions[1].GetName()}, keys,
+		"replica should be filled from the authoritative store")
+	assertions.Len(primaryStore.Applied(), 2, "authoritative store should not be reconciled against itself")
+	assertions.Empty(sinkStore.Applied(), "sinks should not be reconciled")
+
+	assertions.NoError(replicaStore.MemoryStore.Delete(context.Background(), subscriptions[0]))
+	manager.reconcileStore(replica)
+	_, err = replicaStore.Read(context.Background(), dataset, subscriptions[0].GetName())
+	assertions.NoError(err, "replica should be reconciled once it has reconnected")
+
+	assertions.NoError(replicaStore.MemoryStore.Delete(context.Background(), subscriptions[1]))
+	results, err := manager.Reconcile(context.Background(), dataSource, &resourceConfig)
+	assertions.NoError(err)
+	if assertions.Len(results, 1) {
+		assertions.Equal(&reconciliation.Result{Dataset: dataset, Store: "replica", Added: 1}, results[0])
+	}
+
+	results, err = manager.Reconcile(context.Background(), dataSource, &resourceConfig)
+	assertions.NoError(err)
+	if assertions.Len(results, 1) {
+		assertions.Equal(&reconciliation.Result{Dataset: dataset, Store: "replica"}, results[0],
+			"stores other than hazelcast should not use the hazelcast reconcile mode")
+	}
+}
+
+func TestStoreManagerStartReconciliation(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	mode := config.Current.Mode
+	config.Current.Mode = config.ModeWatcher
+	defer func() { config.Current.Mode = mode }()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	resourceConfig := config.Resource{}
+	resourceConfig.Kubernetes.Group = subscriptions[0].GroupVersionKind().Group
+	resourceConfig.Kubernetes.Version = subscriptions[0].GroupVersionKind().Version
+	resourceConfig.Kubernetes.Resource = "subscriptions"
+	resourceConfig.Kubernetes.Kind = subscriptions[0].GetKind()
+
+	primaryStore := newFlakyStore(0)
+	assertions.NoError(primaryStore.Create(context.Background(), subscriptions[0]))
+	replicaStore := newFlakyStore(0)
+
+	manager := newStoreManager("test-manager-start-reconciliation", []string{"primary", "replica"}, config.WriteConsistencyPrimaryOnlyAck, config.ReadFailover{})
+	defer manager.cancel()
+	manager.primary = &ManagedStore{Store: primaryStore, Type: "primary", Role: config.StoreRoleAuthoritative}
+	replica := &ManagedStore{Store: replicaStore, Type: "replica", Role: config.StoreRoleReplica}
+	manager.stores = []*ManagedStore{manager.primary, replica}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dataSource := reconciliation.NewDataSourceFromStore(manager, resourceConfig)
+	manager.InitializeStores(dataSource, &resourceConfig)
+	manager.StartReconciliation(ctx, dataSource, &resourceConfig)
+	assertions.Len(manager.getDatasetReconciliations(resourceConfig.GetGroupVersionName()), 1)
+
+	cancel()
+	assertions.Eventually(func() bool {
+		return len(manager.getReconciliations(replica)) == 0
+	}, time.Second, 10*time.Millisecond, "reconciliations should be removed once the context is done")
+
+	manager.reconcileStore(replica)
+	assertions.Empty(replicaStore.Applied(), "reconnects should not reconcile the store after the context is done")
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Verifier is implemented by store managers that can compare their replicas with the authoritative store.
+type Verifier interface {
+	Verify(ctx context.Context, datasets []string, repair bool) ([]*VerificationReport, error)
+}
+
+// VerificationReport lists the differences between a replica and the authoritative store for a dataset.
+// Keys are "<namespace>/<name>" of the resources.
+type VerificationReport struct {
+	Dataset  string   `json:"dataset"`
+	Replica  string   `json:"replica"`
+	Missing  []string `json:"missing"`
+	Stale    []string `json:"stale"`
+	Orphaned []string `json:"orphaned"`
+	Repaired int      `json:"repaired"`
+}
+
+// Consistent returns whether the replica did not differ from the authoritative store.
+func (r *VerificationReport) Consistent() bool {
+	return len(r.Missing) == 0 && len(r.Stale) == 0 && len(r.Orphaned) == 0
+}
+
+// Verify compares every replica with the authoritative store for each of the datasets and, if repair is set,
+// writes missing and stale resources to the replica and deletes orphaned resources from it.
+// Errors of single datasets are joined and do not stop the verification of the remaining datasets.
+func (m *StoreManager) Verify(ctx context.Context, datasets []string, repair bool) ([]*VerificationReport, error) {
+	reports := make([]*VerificationReport, 0)
+	var errs []error
+
+	for _, dataset := range datasets {
+		for _, replica := range m.stores {
+			if replica.Role != config.StoreRoleReplica {
+				continue
+			}
+
+			report, err := verifyDataset(ctx, m.primary, replica, dataset, repair)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("dataset %q, replica %q: %w", dataset, replica.Type, err))
+				continue
+			}
+
+			setVerificationMetrics(report)
+			if !report.Consistent() {
+				m.logger.Warn().
+					Str("dataset", dataset).
+					Str("replica", replica.Type).
+					Int("missing", len(report.Missing)).
+					Int("stale", len(report.Stale)).
+					Int("orphaned", len(report.Orphaned)).
+					Int("repaired", report.Repaired).
+					Msg("Replica is not consistent with authoritative store")
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	return reports, errors.Join(errs...)
+}
+
+// verifyPeriodically verifies all initialized datasets until the manager is shut down.
+func (m *StoreManager) verifyPeriodically(interval time.Duration, repair bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+
+		case <-ticker.C:
+			if _, err := m.Verify(m.ctx, m.getDatasets(), repair); err != nil {
+				m.logger.Error().Err(err).Msg("Could not verify replicas")
+			}
+		}
+	}
+}
+
+func verifyDataset(ctx context.Context, primary Store, replica Store, dataset string, repair bool) (*VerificationReport, error) {
+	primaryItems, err := primary.List(ctx, dataset, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaItems, err := replica.List(ctx, dataset, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryByKey := itemsByKey(primaryItems)
+	replicaByKey := itemsByKey(replicaItems)
+
+	report := &VerificationReport{
+		Dataset:  dataset,
+		Missing:  make([]string, 0),
+		Stale:    make([]string, 0),
+		Orphaned: make([]string, 0),
+	}
+	if managedStore, ok := replica.(*ManagedStore); ok {
+		report.Replica = managedStore.Type
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(primaryByKey)) {
+		replicaObj, ok := replicaByKey[key]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, key)
+		case !utils.Equivalent(primaryByKey[key], replicaObj):
+			report.Stale = append(report.Stale, key)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(replicaByKey)) {
+		if _, ok := primaryByKey[key]; !ok {
+			report.Orphaned = append(report.Orphaned, key)
+		}
+	}
+
+	if repair {
+		err = repairReplica(ctx, replica, report, primaryByKey, replicaByKey)
+	}
+	return report, err
+}
+
+func repairReplica(
+	ctx context.Context,
+	replica Store,
+	report *VerificationReport,
+	primaryByKey map[string]*unstructured.Unstructured,
+	replicaByKey map[string]*unstructured.Unstructured,
+) error {
+	var errs []error
+	repaired := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		report.Repaired++
+	}
+
+	for _, key := range report.Missing {
+		repaired(replica.Create(ctx, primaryByKey[key]))
+	}
+	for _, key := range report.Stale {
+		repaired(replica.Update(ctx, replicaByKey[key], primaryByKey[key]))
+	}
+	for _, key := range report.Orphaned {
+		repaired(replica.Delete(ctx, replicaByKey[key]))
+	}
+
+	return errors.Join(errs...)
+}
+
+func itemsByKey(items []unstructured.Unstructured) map[string]*unstructured.Unstructured {
+	byKey := make(map[string]*unstructured.Unstructured, len(items))
+	for i := range items {
+		byKey[namespacedName(&items[i])] = &items[i]
+	}
+	return byKey
+}
+
+func setVerificationMetrics(report *VerificationReport) {
+	prefix := report.Dataset + "_" + report.Replica
+	metrics.GetOrCreateCustom(prefix + "_missing_count").WithLabelValues().Set(float64(len(report.Missing)))
+	metrics.GetOrCreateCustom(prefix + "_stale_count").WithLabelValues().Set(float64(len(report.Stale)))
+	metrics.GetOrCreateCustom(prefix + "_orphaned_count").WithLabelValues().Set(float64(len(report.Orphaned)))
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+	"github.com/telekom/quasar/internal/utils"
+)
+
+// TestStoreManagerVerify tests that missing, stale and orphaned resources of a replica are reported and repaired
+func TestStoreManagerVerify(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	dataset := utils.GetGroupVersionId(subscriptions[0])
+
+	updated := subscriptions[0].DeepCopy()
+	updated.SetResourceVersion("2")
+	orphan := subscriptions[1].DeepCopy()
+	orphan.SetName("orphaned-subscription")
+
+	primaryStore := newFlakyStore(0)
+	assertions.NoError(primaryStore.Create(context.Background(), updated))
+	assertions.NoError(primaryStore.Create(context.Background(), subscriptions[1]))
+
+	replicaStore := newFlakyStore(0)
+	assertions.NoError(replicaStore.Create(context.Background(), subscriptions[0]))
+	assertions.NoError(replicaStore.Create(context.Background(), orphan))
+
+	manager := newStoreManager("test-manager-verify", []string{"primary", "replica"}, config.WriteConsistencyPrimaryOnlyAck, config.ReadFailover{})
+	manager.primary = &ManagedStore{Store: primaryStore, Type: "primary", Role: config.StoreRoleAuthoritative}
+	replica := &ManagedStore{Store: replicaStore, Type: "replica", Role: config.StoreRoleReplica}
+	sink := &ManagedStore{Store: newFlakyStore(0), Type: "sink", Role: config.StoreRoleSink}
+	manager.stores = []*ManagedStore{manager.primary, replica, sink}
+
+	reports, err := manager.Verify(context.Background(), []string{dataset}, false)
+	assertions.NoError(err)
+	if assertions.Len(reports, 1, "sinks should not be verified") {
+		report := reports[0]
+		assertions.False(report.Consistent())
+		assertions.Equal("replica", report.Replica)
+		assertions.Equal([]string{namespacedName(subscriptions[1])}, report.Missing)
+		assertions.Equal([]string{namespacedName(updated)}, report.Stale)
+		assertions.Equal([]string{namespacedName(orphan)}, report.Orphaned)
+		assertions.Equal(0, report.Repaired)
+	}
+
+	reports, err = manager.Verify(context.Background(), []string{dataset}, true)
+	assertions.NoError(err)
+	if assertions.Len(reports, 1) {
+		assertions.Equal(3, reports[0].Repaired)
+	}
+
+	reports, err = manager.Verify(context.Background(), []string{dataset}, false)
+	assertions.NoError(err)
+	if assertions.Len(reports, 1) {
+		assertions.True(reports[0].Consistent(), "replica should be consistent after repair")
+	}
+
+	obj, err := replicaStore.Read(context.Background(), dataset, updated.GetName())
+	assertions.NoError(err)
+	assertions.Equal("2", obj.GetResourceVersion())
+}
+
+// TestEquivalent tests that resources are compared by resource version or, if missing, by content
+func TestEquivalent(t *testing.T) {
+	assertions := assert.New(t)
+
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+	a := subscriptions[0].DeepCopy()
+	b := subscriptions[0].DeepCopy()
+	b.SetLabels(map[string]string{"verify_test": "true"})
+	assertions.True(utils.Equivalent(a, b), "resources with the same resource version should be equivalent")
+
+	a.SetResourceVersion("")
+	assertions.False(utils.Equivalent(a, b), "resources with different content should not be equivalent")
+
+	b = a.DeepCopy()
+	b.Object["_id"] = "some-id"
+	assertions.True(utils.Equivalent(a, b), "fields added by stores should be ignored")
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/metrics"
+	"github.com/telekom/quasar/internal/reconciliation"
+	"github.com/telekom/quasar/internal/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	webhookContentType     = "application/cloudevents+json"
+	webhookSignatureHeader = "X-Quasar-Signature"
+	webhookEventTypePrefix = "de.telekom.quasar.resource."
+)
+
+// CloudEvent is the structured-mode CloudEvents 1.0 envelope sent by the WebhookStore.
+type CloudEvent struct {
+	SpecVersion     string           `json:"specversion"`
+	Id              string           `json:"id"`
+	Source          string           `json:"source"`
+	Type            string           `json:"type"`
+	Subject         string           `json:"subject"`
+	Time            time.Time        `json:"time"`
+	DataContentType string           `json:"datacontenttype"`
+	Data            CloudEventChange `json:"data"`
+}
+
+type CloudEventChange struct {
+	Dataset   string         `json:"dataset"`
+	OldObject map[string]any `json:"oldObject,omitempty"`
+	NewObject map[string]any `json:"newObject,omitempty"`
+}
+
+// WebhookStore is a write-only store that POSTs a CloudEvent for every write to the webhook configured for the
+// resource (or the default webhook). Failed deliveries are retried with exponential backoff and requests are signed
+// with an HMAC-SHA256 of the body if a secret is configured. It can only be used as sink.
+type WebhookStore struct {
+	client    *http.Client
+	ctx       context.Context
+	cancel    context.CancelFunc
+	connected atomic.Bool
+}
+
+func (w *WebhookStore) Initialize() {
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.client = &http.Client{Timeout: config.Current.Store.Webhook.Timeout}
+
+	w.connected.Store(true)
+	log.Info().Str("url", config.Current.Store.Webhook.Url).Msg("Webhook store initialized")
+}
+
+func (w *WebhookStore) InitializeResource(dataSource reconciliation.DataSource, resourceConfig *config.Resource) {
+	_ = dataSource
+	resource := resourceConfig.GetGroupVersionResource()
+	log.Debug().Fields(utils.CreateFieldForResource(&resource)).
+		Str("url", resourceConfig.WebhookUrl).
+		Msg("Sending resource changes to webhook")
+}
+
+func (w *WebhookStore) Create(ctx context.Context, obj *unstructured.Unstructured) error {
+	return w.notify(ctx, "created", nil, obj)
+}
+
+func (w *WebhookStore) Update(ctx context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	return w.notify(ctx, "updated", oldObj, newObj)
+}
+
+func (w *WebhookStore) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	return w.notify(ctx, "deleted", obj, nil)
+}
+
+func (w *WebhookStore) Count(_ context.Context, dataset string) (int, error) {
+	return 0, ErrWriteOnlyStore
+}
+
+func (w *WebhookStore) Keys(_ context.Context, dataset string) ([]string, error) {
+	return nil, ErrWriteOnlyStore
+}
+
+func (w *WebhookStore) Read(_ context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
+	return nil, ErrWriteOnlyStore
+}
+
+func (w *WebhookStore) List(_ context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+	return nil, ErrWriteOnlyStore
+}
+
+func (w *WebhookStore) Shutdown() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.connected.Store(false)
+}
+
+func (w *WebhookStore) Connected() bool {
+	return w.connected.Load()
+}
+
+func (w *WebhookStore) WriteOnly() bool {
+	return true
+}
+
+func (w *WebhookStore) notify(ctx context.Context, action string, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+	obj := newObj
+	if obj == nil {
+		obj = oldObj
+	}
+	dataset := utils.GetGroupVersionId(obj)
+
+	url := webhookUrl(obj)
+	if url == "" {
+		log.Debug().
+			Fields(utils.CreateFieldsForCollection(dataset, action, obj)).
+			Msg("No webhook configured for resource, skipping notification")
+		return nil
+	}
+
+	body, err := json.Marshal(createCloudEvent(action, oldObj, newObj))
+	if err == nil {
+		err = w.deliver(ctx, url, body)
+	}
+
+	if err != nil {
+		w.connected.Store(false)
+		metrics.GetOrCreateCustomCounter("webhook_delivery_errors_total").WithLabelValues().Inc()
+		log.Error().Err(err).
+			Fields(utils.CreateFieldsForCollection(dataset, action, obj)).
+			Str("url", url).
+			Msg("Could not deliver resource change to webhook")
+		return err
+	}
+
+	w.connected.Store(true)
+	metrics.GetOrCreateCustomCounter("webhook_delivered_events_total").WithLabelValues().Inc()
+	log.Debug().
+		Fields(utils.CreateFieldsForCollection(dataset, action, obj)).
+		Str("url", url).
+		Msg("Resource change delivered to webhook")
+	return nil
+}
+
+// deliver posts the body to the url and retries with exponential backoff until the webhook accepts it,
+// the error is not retryable, the maximum number of attempts has been reached or the context or store is done.
+func (w *WebhookStore) deliver(ctx context.Context, url string, body []byte) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(w.ctx, cancel)
+	defer stop()
+
+	retryConfig := config.Current.Store.Webhook.Retry
+	backoff := retryConfig.InitialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		if retryable, err = w.post(ctx, url, body); err == nil || !retryable || attempt >= retryConfig.MaxAttempts {
+			return err
+		}
+
+		log.Debug().Err(err).Int("attempt", attempt).Dur("backoff", backoff).Msg("Webhook delivery failed, retrying")
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+
+		backoff = time.Duration(float64(backoff) * retryConfig.Multiplier)
+		if retryConfig.MaxBackoff > 0 && backoff > retryConfig.MaxBackoff {
+			backoff = retryConfig.MaxBackoff
+		}
+	}
+}
+
+// post sends a single request and reports whether a failure is worth retrying.
+func (w *WebhookStore) post(ctx context.Context, url string, body []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	request.Header.Set("Content-Type", webhookContentType)
+	if secret := config.Current.Store.Webhook.Secret; secret != "" {
+		request.Header.Set(webhookSignatureHeader, signWebhookBody(secret, body))
+	}
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+
+	retryable := response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("%w: %s responded with status %d", ErrWebhookDeliveryFailed, url, response.StatusCode)
+}
+
+func createCloudEvent(action string, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) *CloudEvent {
+	obj := newObj
+	if obj == nil {
+		obj = oldObj
+	}
+
+	event := &CloudEvent{
+		SpecVersion:     "1.0",
+		Id:              uuid.NewString(),
+		Source:          config.Current.Store.Webhook.Source,
+		Type:            webhookEventTypePrefix + action,
+		Subject:         namespacedName(obj),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data: CloudEventChange{
+			Dataset: utils.GetGroupVersionId(obj),
+		},
+	}
+	if oldObj != nil {
+		event.Data.OldObject = oldObj.Object
+	}
+	if newObj != nil {
+		event.Data.NewObject = newObj.Object
+	}
+
+	return event
+}
+
+// signWebhookBody returns the value of the signature header, which is the hex-encoded HMAC-SHA256 of the body.
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookUrl returns the webhook configured for the resource or falls back to the default webhook.
+func webhookUrl(obj *unstructured.Unstructured) string {
+	if resourceConfig, ok := config.Current.GetResourceConfiguration(obj); ok && resourceConfig.WebhookUrl != "" {
+		return resourceConfig.WebhookUrl
+	}
+	return config.Current.Store.Webhook.Url
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Deutsche Telekom AG
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build testing
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/telekom/quasar/internal/config"
+	"github.com/telekom/quasar/internal/test"
+)
+
+func createWebhookStore(t *testing.T, url string) *WebhookStore {
+	t.Helper()
+
+	previousWebhookConfig := config.Current.Store.Webhook
+	config.Current.Store.Webhook = config.Webhook{
+		Url:     url,
+		Source:  "quasar-test",
+		Secret:  "s3cr3t",
+		Timeout: 5 * time.Second,
+		Retry: config.WebhookRetry{
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     20 * time.Millisecond,
+			Multiplier:     2,
+		},
+	}
+	t.Cleanup(func() {
+		config.Current.Store.Webhook = previousWebhookConfig
+	})
+
+	webhookStore := new(WebhookStore)
+	webhookStore.Initialize()
+	t.Cleanup(webhookStore.Shutdown)
+
+	return webhookStore
+}
+
+func TestWebhookStore_CloudEvent(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	var event CloudEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		assertions.Equal(webhookContentType, r.Header.Get("Content-Type"))
+		assertions.Equal(signWebhookBody("s3cr3t", body), r.Header.Get(webhookSignatureHeader))
+		event = CloudEvent{}
+		assertions.NoError(json.Unmarshal(body, &event))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhookStore := createWebhookStore(t, server.URL)
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+
+	assertions.NoError(webhookStore.Update(context.Background(), subscriptions[0], subscriptions[0]))
+	assertions.True(webhookStore.Connected())
+
+	assertions.Equal("1.0", event.SpecVersion)
+	assertions.Equal("quasar-test", event.Source)
+	assertions.Equal("de.telekom.quasar.resource.updated", event.Type)
+	assertions.Equal("playground/"+subscriptions[0].GetName(), event.Subject)
+	assertions.NotEmpty(event.Id)
+	assertions.NotNil(event.Data.OldObject)
+	assertions.NotNil(event.Data.NewObject)
+
+	assertions.NoError(webhookStore.Delete(context.Background(), subscriptions[0]))
+	assertions.Equal("de.telekom.quasar.resource.deleted", event.Type)
+	assertions.Nil(event.Data.NewObject)
+}
+
+func TestWebhookStore_Retry(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhookStore := createWebhookStore(t, server.URL)
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+
+	assertions.NoError(webhookStore.Create(context.Background(), subscriptions[0]), "delivery should succeed on the third attempt")
+	assertions.Equal(int32(3), requests.Load())
+
+	requests.Store(0)
+	config.Current.Store.Webhook.Retry.MaxAttempts = 2
+	err := webhookStore.Create(context.Background(), subscriptions[0])
+	assertions.ErrorIs(err, ErrWebhookDeliveryFailed, "delivery should fail once all attempts are used up")
+	assertions.Equal(int32(2), requests.Load())
+	assertions.False(webhookStore.Connected())
+}
+
+func TestWebhookStore_NoRetryOnClientError(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	webhookStore := createWebhookStore(t, server.URL)
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+
+	assertions.ErrorIs(webhookStore.Create(context.Background(), subscriptions[0]), ErrWebhookDeliveryFailed)
+	assertions.Equal(int32(1), requests.Load(), "client errors should not be retried")
+}
+
+func TestWebhookStore_ResourceUrl(t *testing.T) {
+	assertions := assert.New(t)
+	defer test.LogRecorder.Reset()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhookStore := createWebhookStore(t, "")
+	subscriptions := test.ReadTestSubscriptions("../../testdata/subscriptions.json")
+
+	assertions.NoError(webhookStore.Create(context.Background(), subscriptions[0]), "resources without webhook should be skipped")
+	assertions.Equal(int32(0), requests.Load())
+
+	previousResources := config.Current.Resources
+	defer func() {
+		config.Current.Resources = previousResources
+	}()
+
+	resourceConfig := config.Resource{WebhookUrl: server.URL}
+	resourceConfig.Kubernetes.Group = "subscriber.horizon.telekom.de"
+	resourceConfig.Kubernetes.Version = "v1"
+	resourceConfig.Kubernetes.Kind = subscriptions[0].GetKind()
+	config.Current.Resources = append(config.Current.Resources, resourceConfig)
+
+	assertions.NoError(webhookStore.Create(context.Background(), subscriptions[0]))
+	assertions.Equal(int32(1), requests.Load())
+}
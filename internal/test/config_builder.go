@@ -8,13 +8,14 @@ package test
 
 import (
 	"net"
+	"time"
 
 	"github.com/telekom/quasar/internal/config"
 )
 
 const defaultHorizonName = "horizon"
 
-// BuildBaseTestConfig creates a base test configuration with MongoDB and Hazelcast setup.
+// BuildBaseTestConfig creates a base test configuration with MongoDB, Hazelcast, Redis and PostgreSQL setup.
 // This configuration can be extended by individual test packages as needed.
 func BuildBaseTestConfig() *config.Configuration {
 	testConfig := new(config.Configuration)
@@ -38,6 +39,46 @@ func BuildBaseTestConfig() *config.Configuration {
 	}
 	testConfig.Store.Hazelcast.ReconcileMode = config.ReconcileModeIncremental
 
+	// Redis configuration
+	testConfig.Store.Redis = config.Redis{
+		Host: EnvOrDefault("REDIS_HOST", "localhost"),
+		Port: 6379,
+	}
+
+	// PostgreSQL configuration
+	postgresHost := EnvOrDefault("POSTGRES_HOST", "localhost")
+	postgresPort := EnvOrDefault("POSTGRES_PORT", "5432")
+	testConfig.Store.Postgres = config.Postgres{
+		Uri:    "postgres://horizon:horizon@" + net.JoinHostPort(postgresHost, postgresPort) + "/horizon",
+		Schema: "public",
+	}
+
+	// Retry queue configuration
+	testConfig.Store.RetryQueue = config.RetryQueue{
+		Capacity:       1000,
+		Workers:        2,
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		DrainTimeout:   5 * time.Second,
+	}
+
+	testConfig.Store.CircuitBreaker = config.CircuitBreaker{
+		Enabled:          true,
+		FailureThreshold: 5,
+		Timeout:          10 * time.Second,
+		OpenDuration:     time.Second,
+		HalfOpenProbes:   1,
+	}
+
+	testConfig.Reconciliation = config.Reconciliation{
+		Mode:            config.ReconcileModeIncremental,
+		Interval:        60 * time.Second,
+		DeleteOrphans:   true,
+		OrphanThreshold: 0.5,
+	}
+
 	return testConfig
 }
 
@@ -14,8 +14,10 @@ import (
 
 	"github.com/hazelcast/hazelcast-go-client"
 	"github.com/hazelcast/hazelcast-go-client/cluster"
+	"github.com/jackc/pgx/v5"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -34,12 +36,24 @@ var (
 	mongoHost  = EnvOrDefault("MONGO_HOST", "0.0.0.0")
 	mongoPort  = EnvOrDefault("MONGO_PORT", "27017")
 
+	redisImage = EnvOrDefault("REDIS_IMAGE", "redis")
+	redisTag   = EnvOrDefault("REDIS_TAG", "7.2")
+	redisHost  = EnvOrDefault("REDIS_HOST", "0.0.0.0")
+	redisPort  = EnvOrDefault("REDIS_PORT", "6379")
+
+	postgresImage = EnvOrDefault("POSTGRES_IMAGE", "postgres")
+	postgresTag   = EnvOrDefault("POSTGRES_TAG", "16")
+	postgresHost  = EnvOrDefault("POSTGRES_HOST", "0.0.0.0")
+	postgresPort  = EnvOrDefault("POSTGRES_PORT", "5432")
+
 	alreadySetUp = false
 )
 
 type Options struct {
 	MongoDb   bool
 	Hazelcast bool
+	Redis     bool
+	Postgres  bool
 }
 
 func SetupDocker(opts *Options) {
@@ -82,6 +96,18 @@ func setupServices(opts *Options) {
 			log.Fatalf("Could not setup hazelcast: %s", err)
 		}
 	}
+
+	if opts.Redis {
+		if err := setupRedis(); err != nil {
+			log.Fatalf("Could not setup redis: %s", err)
+		}
+	}
+
+	if opts.Postgres {
+		if err := setupPostgres(); err != nil {
+			log.Fatalf("Could not setup postgres: %s", err)
+		}
+	}
 }
 
 func waitForServicesReady(opts *Options) {
@@ -100,6 +126,18 @@ func waitForServicesReady(opts *Options) {
 			}
 		}
 
+		if opts.Redis {
+			if err := pingRedis(); err != nil {
+				return err
+			}
+		}
+
+		if opts.Postgres {
+			if err := pingPostgres(); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -112,6 +150,12 @@ func waitForServicesReady(opts *Options) {
 	if opts.Hazelcast {
 		log.Println("Hazelcast is ready!")
 	}
+	if opts.Redis {
+		log.Println("Redis is ready!")
+	}
+	if opts.Postgres {
+		log.Println("PostgreSQL is ready!")
+	}
 }
 
 func TeardownDocker() {
@@ -183,6 +227,65 @@ func pingHazelcast() error {
 	return client.Shutdown(ctx)
 }
 
+func setupRedis() error {
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Name:         "quasar-redis",
+		Repository:   redisImage,
+		Tag:          redisTag,
+		ExposedPorts: []string{"6379/tcp"},
+		PortBindings: map[docker.Port][]docker.PortBinding{
+			"6379/tcp": {{HostIP: redisHost, HostPort: redisPort}},
+		},
+	}, configureTeardown)
+	resources = append(resources, resource)
+	return err
+}
+
+func pingRedis() error {
+	client := redis.NewClient(&redis.Options{
+		Addr: net.JoinHostPort(redisHost, redisPort),
+	})
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		log.Printf("Could not reach redis: %s\n", err)
+		return err
+	}
+	return nil
+}
+
+func setupPostgres() error {
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Name:         "quasar-postgres",
+		Repository:   postgresImage,
+		Tag:          postgresTag,
+		ExposedPorts: []string{"5432/tcp"},
+		PortBindings: map[docker.Port][]docker.PortBinding{
+			"5432/tcp": {{HostIP: postgresHost, HostPort: postgresPort}},
+		},
+		Env: []string{
+			"POSTGRES_USER=horizon",
+			"POSTGRES_PASSWORD=horizon",
+			"POSTGRES_DB=horizon",
+		},
+	}, configureTeardown)
+	resources = append(resources, resource)
+	return err
+}
+
+func pingPostgres() error {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, "postgres://horizon:horizon@"+net.JoinHostPort(postgresHost, postgresPort)+"/horizon")
+	if err != nil {
+		log.Printf("Could not reach postgres: %s\n", err)
+		return err
+	}
+
+	return conn.Close(ctx)
+}
+
 func configureTeardown(config *docker.HostConfig) {
 	config.AutoRemove = true
 	config.RestartPolicy = docker.RestartPolicy{
@@ -7,6 +7,8 @@
 package test
 
 import (
+	"context"
+
 	"github.com/telekom/quasar/internal/config"
 	"github.com/telekom/quasar/internal/reconciliation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -29,38 +31,38 @@ func (s *DummyStore) InitializeResource(reconciliation.DataSource, *config.Resou
 	s.HasInitializedResource = true
 }
 
-func (s *DummyStore) Create(*unstructured.Unstructured) error {
+func (s *DummyStore) Create(context.Context, *unstructured.Unstructured) error {
 	s.AddCalls++
 	return nil
 }
 
-func (s *DummyStore) Update(oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
+func (s *DummyStore) Update(_ context.Context, oldObj *unstructured.Unstructured, newObj *unstructured.Unstructured) error {
 	_, _ = oldObj, newObj
 	s.UpdateCalls++
 	return nil
 }
 
-func (s *DummyStore) Delete(*unstructured.Unstructured) error {
+func (s *DummyStore) Delete(context.Context, *unstructured.Unstructured) error {
 	s.DeleteCalls++
 	return nil
 }
 
-func (s *DummyStore) Count(dataset string) (int, error) {
+func (s *DummyStore) Count(_ context.Context, dataset string) (int, error) {
 	_ = dataset
 	panic("not implemented")
 }
 
-func (s *DummyStore) Keys(dataset string) ([]string, error) {
+func (s *DummyStore) Keys(_ context.Context, dataset string) ([]string, error) {
 	_ = dataset
 	panic("not implemented")
 }
 
-func (s *DummyStore) Read(dataset string, key string) (*unstructured.Unstructured, error) {
+func (s *DummyStore) Read(_ context.Context, dataset string, key string) (*unstructured.Unstructured, error) {
 	_, _ = dataset, key
 	panic("not implemented")
 }
 
-func (s *DummyStore) List(dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
+func (s *DummyStore) List(_ context.Context, dataset string, fieldSelector string, limit int64) ([]unstructured.Unstructured, error) {
 	_, _, _ = dataset, fieldSelector, limit
 	panic("not implemented")
 }
@@ -5,7 +5,11 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"strconv"
 	"strings"
 
@@ -95,3 +99,57 @@ func MatchFieldSelector(obj *unstructured.Unstructured, fieldSelector string) bo
 	}
 	return strings.Contains(string(jsonBytes), fieldSelector)
 }
+
+// ParseFieldSelector parses a comma separated list of key=value pairs into a map of field paths and expected values.
+// Malformed selectors and selectors without a key are ignored.
+func ParseFieldSelector(fieldSelector string) map[string]string {
+	fields := make(map[string]string)
+
+	for _, selector := range strings.Split(fieldSelector, ",") {
+		key, value, found := strings.Cut(selector, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+
+	return fields
+}
+
+// MatchFields returns whether all field paths of the given map resolve to the expected value in the object.
+func MatchFields(obj *unstructured.Unstructured, fields map[string]string) bool {
+	for path, expected := range fields {
+		value, ok, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(path, ".")...)
+		if err != nil || !ok {
+			return false
+		}
+
+		if fmt.Sprintf("%v", value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+// Equivalent compares the resource versions of both resources or, if one of them has none, their contents.
+func Equivalent(a *unstructured.Unstructured, b *unstructured.Unstructured) bool {
+	if versionA, versionB := a.GetResourceVersion(), b.GetResourceVersion(); versionA != "" && versionB != "" {
+		return versionA == versionB
+	}
+	return ContentHash(a) == ContentHash(b)
+}
+
+// ContentHash returns the SHA-256 of the JSON representation of the resource, ignoring fields added by stores.
+func ContentHash(obj *unstructured.Unstructured) string {
+	content := maps.Clone(obj.Object)
+	delete(content, "_id")
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}